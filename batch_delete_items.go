@@ -3,20 +3,20 @@ package dynago
 import (
 	"context"
 
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 /**
 * Used to batch delete records from  dynamodb
-* @param input slice of record want to  put to DB
-* @return error
+* Unprocessed keys are retried using ClientOptions.BatchRetryPolicy
+* @param input slice of keys of the records to delete
+* @return *BatchWriteError listing the keys that were not deleted
  */
-func (t *Client) BatchDeleteItems(ctx context.Context, input []map[string]types.AttributeValue) []map[string]types.AttributeValue {
+func (t *Client) BatchDeleteItems(ctx context.Context, input []map[string]types.AttributeValue) (err error) {
+	ctx, op := t.startOperation(ctx, "BatchDeleteItems")
+	defer func() { op.end(err) }()
+
 	items := make([]types.WriteRequest, 0, len(input))
-	errorRequests := make([]types.WriteRequest, 0, len(input))
-	failedItems := make([]map[string]types.AttributeValue, 0, len(input))
-	table := t.TableName
 	for _, model := range input {
 		items = append(items,
 			types.WriteRequest{
@@ -26,29 +26,5 @@ func (t *Client) BatchDeleteItems(ctx context.Context, input []map[string]types.
 			},
 		)
 	}
-	chunkedItems := chunkBy(items, ChunkSize)
-	for _, chunkedBatch := range chunkedItems {
-		output, err := t.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]types.WriteRequest{
-				table: chunkedBatch,
-			},
-		})
-		if err != nil {
-			errorRequests = append(errorRequests, chunkedBatch...)
-		} else {
-			if len(output.UnprocessedItems) > 0 {
-				unprocessedItems := output.UnprocessedItems[table]
-				errorRequests = append(errorRequests, unprocessedItems...)
-			}
-		}
-	}
-
-	if len(errorRequests) > 0 {
-		for _, failedReq := range errorRequests {
-			failedItems = append(failedItems, failedReq.DeleteRequest.Key)
-		}
-	}
-
-	return failedItems
-
+	return t.batchWrite(ctx, items)
 }
@@ -33,7 +33,7 @@ func getBatchResult(ctx context.Context, t *Client, keys []AttributeRecord) ([]A
 		}
 		res, err := t.client.BatchGetItem(ctx, input)
 		if err != nil {
-			return nil, err
+			return nil, wrapError(err)
 		}
 
 		items = append(items, res.Responses[table]...)
@@ -47,15 +47,20 @@ func getBatchResult(ctx context.Context, t *Client, keys []AttributeRecord) ([]A
 }
 
 func (t *Client) BatchGetItems(ctx context.Context, input []AttributeRecord, out interface{}) (err error) {
+	ctx, op := t.startOperation(ctx, "BatchGetItems")
+	defer func() { op.end(err) }()
+
 	var items = make([]AttributeRecord, 0, len(input))
 	var batches = chunkBy(input, 100)
 	for _, batch := range batches {
+		op.addChunk()
 		res, err := getBatchResult(ctx, t, batch)
 		if err != nil {
 			return err
 		}
 		items = append(items, res...)
 	}
+	op.addItems(len(items))
 	err = attributevalue.UnmarshalListOfMaps(items, &out)
 	if err != nil {
 		return err
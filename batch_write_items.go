@@ -2,6 +2,7 @@ package dynago
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -9,16 +10,56 @@ import (
 
 const ChunkSize = 25
 
+// BatchWriteError is returned by batch writes when some requests were still not processed after all retry attempts
+type BatchWriteError struct {
+	// Items that were not put
+	Puts []AttributeRecord
+	// Keys of the items that were not deleted
+	Deletes []AttributeRecord
+	// Last request error, nil if DynamoDB kept returning the requests as unprocessed
+	Err error
+}
+
+func newBatchWriteError(requests []types.WriteRequest, err error) *BatchWriteError {
+	e := &BatchWriteError{Err: err}
+	for _, req := range requests {
+		if req.PutRequest != nil {
+			e.Puts = append(e.Puts, req.PutRequest.Item)
+		}
+		if req.DeleteRequest != nil {
+			e.Deletes = append(e.Deletes, req.DeleteRequest.Key)
+		}
+	}
+	return e
+}
+
+func (e *BatchWriteError) Error() string {
+	msg := fmt.Sprintf("batch write failed; %d put and %d delete requests were not processed", len(e.Puts), len(e.Deletes))
+	if e.Err != nil {
+		msg += "; " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *BatchWriteError) Unwrap() error {
+	return e.Err
+}
+
 /**
 * Used to update records to  dynamodb
+* Unprocessed items are retried using ClientOptions.BatchRetryPolicy
 * @param input slice of record want to  put to DB
-* @return error
+* @return *BatchWriteError listing the items that were not written
  */
+func (t *Client) BatchWriteItems(ctx context.Context, input []map[string]types.AttributeValue) (err error) {
+	ctx, op := t.startOperation(ctx, "BatchWriteItems")
+	defer func() { op.end(err) }()
 
-func (t *Client) BatchWriteItems(ctx context.Context, input []map[string]types.AttributeValue) error {
 	items := make([]types.WriteRequest, 0, len(input))
-	table := t.TableName
-	for _, model := range input {
+	for idx, model := range input {
+		if err := checkItemSize(idx, model); err != nil {
+			return err
+		}
 		items = append(items,
 			types.WriteRequest{
 				PutRequest: &types.PutRequest{
@@ -27,18 +68,65 @@ func (t *Client) BatchWriteItems(ctx context.Context, input []map[string]types.A
 			},
 		)
 	}
-	chunkedItems := chunkBy(items, ChunkSize)
-	for _, chunkedBatch := range chunkedItems {
-		_, err := t.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]types.WriteRequest{
-				table: chunkedBatch,
-			},
-		})
+	return t.batchWrite(ctx, items)
+}
+
+// batchWrite sends requests in chunks of ChunkSize; failed requests of all chunks are collected into a BatchWriteError
+func (t *Client) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	var (
+		failed  []types.WriteRequest
+		lastErr error
+	)
+	op := operationFrom(ctx)
+	for _, chunk := range chunkBy(requests, ChunkSize) {
+		if len(chunk) == 0 {
+			continue
+		}
+		op.addChunk()
+		// do not attempt remaining chunks once the context is cancelled
+		if err := ctx.Err(); err != nil {
+			failed = append(failed, chunk...)
+			lastErr = err
+			continue
+		}
+		unprocessed, err := t.writeChunk(ctx, chunk)
+		op.addItems(len(chunk) - len(unprocessed))
+		failed = append(failed, unprocessed...)
 		if err != nil {
-			return err
+			lastErr = err
 		}
 	}
 
+	if len(failed) > 0 {
+		return newBatchWriteError(failed, lastErr)
+	}
 	return nil
+}
+
+// writeChunk writes up to ChunkSize requests, retrying unprocessed requests with backoff.
+// Returns the requests that were not processed
+func (t *Client) writeChunk(ctx context.Context, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	table := t.TableName
+	policy := t.batchRetry.orDefault()
+	for attempt := 1; ; attempt++ {
+		output, err := t.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{
+				table: requests,
+			},
+		})
+		if err != nil {
+			return requests, wrapError(err)
+		}
 
+		requests = output.UnprocessedItems[table]
+		if len(requests) == 0 {
+			return nil, nil
+		}
+		if attempt >= policy.MaxAttempts {
+			return requests, nil
+		}
+		if err := policy.wait(ctx, attempt); err != nil {
+			return requests, err
+		}
+	}
 }
@@ -0,0 +1,173 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrDuplicateKey is returned by BatchWriter when a chunk already contains a request for the same key.
+// DynamoDB rejects batches that put or delete the same item twice
+var ErrDuplicateKey = errors.New("duplicate key in batch")
+
+type BatchWriterOptions struct {
+	// Number of chunks written concurrently, defaults to 1
+	Parallelism int
+}
+
+// BatchWriter collects put and delete requests into chunks of ChunkSize and writes each chunk with BatchWriteItem
+// once it is full. Remaining requests are written by Flush or Close.
+// Unprocessed requests are retried using ClientOptions.BatchRetryPolicy
+//
+// When Parallelism is greater than 1, requests for the same key in different chunks may be applied in any order
+//
+//	w := table.NewBatchWriter(ctx, dynago.BatchWriterOptions{Parallelism: 4})
+//	for _, user := range users {
+//	  if err := w.Put(dynago.StringValue(user.Pk), dynago.StringValue(user.Sk), user); err != nil {
+//	    return err
+//	  }
+//	}
+//	err := w.Close()
+type BatchWriter struct {
+	ctx    context.Context
+	client *Client
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	pending []types.WriteRequest
+	keys    map[string]struct{}
+	closed  bool
+	// number of requests accepted, used as the index of items in errors
+	added int
+
+	// failures are guarded separately, mu is held while waiting for a chunk to finish
+	errMu   sync.Mutex
+	failed  []types.WriteRequest
+	lastErr error
+}
+
+// NewBatchWriter creates a BatchWriter that writes to the table of the client
+func (t *Client) NewBatchWriter(ctx context.Context, opts BatchWriterOptions) *BatchWriter {
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	return &BatchWriter{
+		ctx:     ctx,
+		client:  t,
+		sem:     make(chan struct{}, parallelism),
+		pending: make([]types.WriteRequest, 0, ChunkSize),
+		keys:    map[string]struct{}{},
+	}
+}
+
+// Put adds a request to create or replace item at the given partition key and sort key
+func (w *BatchWriter) Put(pk, sk Attribute, item interface{}) error {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item; %w", err)
+	}
+	key := w.client.NewKeys(pk, sk)
+	for k, v := range key {
+		av[k] = v
+	}
+	return w.add(key, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+}
+
+// Delete adds a request to delete the item at the given partition key and sort key
+func (w *BatchWriter) Delete(pk, sk Attribute) error {
+	key := w.client.NewKeys(pk, sk)
+	return w.add(key, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+}
+
+func (w *BatchWriter) add(key map[string]Attribute, req types.WriteRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return fmt.Errorf("batch writer is closed")
+	}
+
+	if req.PutRequest != nil {
+		if err := checkItemSize(w.added, req.PutRequest.Item); err != nil {
+			return err
+		}
+	}
+	id := keyString(key)
+	if _, ok := w.keys[id]; ok {
+		return fmt.Errorf("%w; %s", ErrDuplicateKey, id)
+	}
+	w.keys[id] = struct{}{}
+	w.added++
+	w.pending = append(w.pending, req)
+
+	if len(w.pending) >= ChunkSize {
+		w.dispatch()
+	}
+	return nil
+}
+
+// dispatch writes the pending chunk in the background. Blocks while Parallelism chunks are being written.
+// Must be called with w.mu held
+func (w *BatchWriter) dispatch() {
+	if len(w.pending) == 0 {
+		return
+	}
+	chunk := w.pending
+	w.pending = make([]types.WriteRequest, 0, ChunkSize)
+	w.keys = map[string]struct{}{}
+
+	w.sem <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			<-w.sem
+			w.wg.Done()
+		}()
+		ctx, op := w.client.startOperation(w.ctx, "BatchWriter")
+		op.addChunk()
+		unprocessed, err := w.client.writeChunk(ctx, chunk)
+		op.addItems(len(chunk) - len(unprocessed))
+		if len(unprocessed) == 0 && err == nil {
+			op.end(nil)
+			return
+		}
+		op.end(newBatchWriteError(unprocessed, err))
+		w.errMu.Lock()
+		defer w.errMu.Unlock()
+		w.failed = append(w.failed, unprocessed...)
+		if err != nil {
+			w.lastErr = err
+		}
+	}()
+}
+
+// Flush writes pending requests and waits for all chunks in flight.
+// Returns a *BatchWriteError listing the requests that failed since the last Flush
+func (w *BatchWriter) Flush() error {
+	w.mu.Lock()
+	w.dispatch()
+	w.mu.Unlock()
+	w.wg.Wait()
+
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	if len(w.failed) == 0 {
+		return nil
+	}
+	err := newBatchWriteError(w.failed, w.lastErr)
+	w.failed, w.lastErr = nil, nil
+	return err
+}
+
+// Close flushes pending requests; no requests can be added afterwards
+func (w *BatchWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	return w.Flush()
+}
@@ -3,27 +3,74 @@ package dynago
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ClientOptions struct {
 	TableName        string
 	Region           string
 	PartitionKeyName string
-	SortKeyName      string
+	// Name of the sort key, leave empty for tables with a partition key only
+	SortKeyName string
+	// Attribute types of the partition and sort keys, types.ScalarAttributeTypeS when not set
+	PartitionKeyType types.ScalarAttributeType
+	SortKeyType      types.ScalarAttributeType
 	Endpoint         *EndpointResolver
 	Middlewares      []func(*aws.Config)
+	// Backoff used to retry unprocessed items of batch writes. DefaultRetryPolicy is used when not set
+	BatchRetryPolicy RetryPolicy
+	// Backoff used to retry transactions canceled by conflicts or throttling, see TransactionCanceledError.Retryable.
+	// DefaultRetryPolicy is used when not set, set MaxAttempts to 1 to disable retries
+	TransactionRetryPolicy RetryPolicy
+	// Logger receives failed requests at error level and lookups of missing items at debug level.
+	// Nothing is logged when not set
+	Logger *slog.Logger
+	// Include key values in log records, values are redacted by default
+	LogKeyValues bool
+	// Secondary indexes of the table, used to build index keys and validate cursors of index queries
+	Indexes []Index
+	// TracerProvider creates a span per operation, eg: GetItem or Query, with the table, index, item count and
+	// consumed capacity. Requests ask DynamoDB for their consumed capacity when set. No spans are created when not set
+	TracerProvider trace.TracerProvider
+	// MeterProvider records the duration and errors of operations, see MetricOperationDuration and
+	// MetricOperationErrors. No metrics are recorded when not set
+	MeterProvider metric.MeterProvider
+}
+
+// DynamoDBAPI is the subset of the AWS SDK DynamoDB client used by Client.
+// It is implemented by *dynamodb.Client and by in-memory backends such as testing/memdb
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	TransactGetItems(ctx context.Context, params *dynamodb.TransactGetItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactGetItemsOutput, error)
 }
 
 type Client struct {
-	client    *dynamodb.Client
-	TableName string
-	Keys      map[string]string
+	client       DynamoDBAPI
+	TableName    string
+	Keys         map[string]string
+	Indexes      map[string]Index
+	keyTypes     map[string]types.ScalarAttributeType
+	batchRetry   RetryPolicy
+	txRetry      RetryPolicy
+	logger       *slog.Logger
+	logKeyValues bool
+	telemetry    *telemetry
 }
 
 type TransactWriteItem types.TransactWriteItem
@@ -45,6 +92,9 @@ type TransactWriteItem types.TransactWriteItem
 //	  Region:           "us-east-1",
 //	})
 func NewClient(ctx context.Context, opt ClientOptions) (*Client, error) {
+	if _, err := keyTypesOf(opt); err != nil {
+		return nil, err
+	}
 	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(opt.Region))
 
 	// if an endpoint url is provided, connect to the remote/local dynamodb instead of AWS hosted dynamodb
@@ -62,24 +112,80 @@ func NewClient(ctx context.Context, opt ClientOptions) (*Client, error) {
 		return nil, fmt.Errorf("unable to load SDK config, %w", err)
 	}
 
-	// aws sdk middlewares, usage tracing
-	// TODO: better implementation, take a tracer impl and do much more
+	// aws sdk middlewares, see ClientOptions.TracerProvider and MeterProvider to instrument dynago operations
 	for _, m := range opt.Middlewares {
 		m(&cfg)
 	}
 
 	// Using the Config value, create the DynamoDB client
 	c := dynamodb.NewFromConfig(cfg)
-	return &Client{
-		client:    c,
+	return NewClientFromAPI(c, opt), nil
+}
+
+// NewClientFromAPI creates a client that sends requests to the given DynamoDB API implementation instead of
+// connecting to AWS. Connection related options (Region, Endpoint and Middlewares) are ignored.
+// Index keys set to another type than the table key of the same name keep the type of the table key and the conflict
+// is logged, NewClient returns an ErrValidation for them instead
+//
+//	db := memdb.New()
+//	table := dynago.NewClientFromAPI(db, dynago.ClientOptions{TableName: "test", PartitionKeyName: "pk", SortKeyName: "sk"})
+func NewClientFromAPI(api DynamoDBAPI, opt ClientOptions) *Client {
+	indexes := make(map[string]Index, len(opt.Indexes))
+	for _, idx := range opt.Indexes {
+		indexes[idx.IndexName] = idx
+	}
+	keyTypes, keyErr := keyTypesOf(opt)
+	api = rawAPI(api)
+	if opt.TracerProvider != nil {
+		api = tracedAPI{api}
+	}
+	c := &Client{
+		client:    api,
 		TableName: opt.TableName,
 		Keys: map[string]string{
 			"pk": opt.PartitionKeyName,
 			"sk": opt.SortKeyName,
 		},
-	}, nil
+		Indexes:      indexes,
+		keyTypes:     keyTypes,
+		batchRetry:   opt.BatchRetryPolicy.orDefault(),
+		txRetry:      opt.TransactionRetryPolicy.orDefault(),
+		logger:       newLogger(opt),
+		logKeyValues: opt.LogKeyValues,
+		telemetry:    newTelemetry(opt),
+	}
+	if keyErr != nil {
+		c.log().Error("invalid key types", slog.Any("error", keyErr))
+	}
+	return c
 }
 
+// GetDynamoDBClient returns the AWS SDK client used by the client.
+// Returns nil when the client was created by NewClientFromAPI with another DynamoDBAPI implementation
 func (t *Client) GetDynamoDBClient() *dynamodb.Client {
-	return t.client
+	c, _ := rawAPI(t.client).(*dynamodb.Client)
+	return c
+}
+
+// ForTable creates a client of another table that sends requests using the connection of t, so items of both
+// tables can be written in a single transaction, see Tx.On. Connection related options are ignored.
+// The logger, with LogKeyValues, and retry policies of t are used unless opt sets them, the same goes for the
+// TracerProvider and MeterProvider of t unless opt sets either of them
+//
+//	inventory := orders.ForTable(dynago.ClientOptions{TableName: "inventory", PartitionKeyName: "sku"})
+func (t *Client) ForTable(opt ClientOptions) *Client {
+	c := NewClientFromAPI(t.client, opt)
+	if opt.Logger == nil {
+		c.logger, c.logKeyValues = t.logger, t.logKeyValues
+	}
+	if opt.BatchRetryPolicy.MaxAttempts < 1 {
+		c.batchRetry = t.batchRetry
+	}
+	if opt.TransactionRetryPolicy.MaxAttempts < 1 {
+		c.txRetry = t.txRetry
+	}
+	if opt.TracerProvider == nil && opt.MeterProvider == nil {
+		c.client, c.telemetry = t.client, t.telemetry
+	}
+	return c
 }
@@ -1,9 +1,16 @@
 package dynago_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/pagination"
 )
 
 func TestClient(t *testing.T) {
@@ -14,3 +21,53 @@ func TestClient(t *testing.T) {
 		t.Errorf("client does not implement DynamoClient interface")
 	}
 }
+
+func TestClientKeyTypesOfSharedKeyNames(t *testing.T) {
+	opt := dynago.ClientOptions{
+		TableName:        "events",
+		PartitionKeyName: "id",
+		PartitionKeyType: types.ScalarAttributeTypeN,
+		SortKeyName:      "at",
+		Indexes: []dynago.Index{
+			{IndexName: "id-index", PartitionKeyName: "id", SortKeyName: "kind"},
+			{IndexName: "kind-index", PartitionKeyName: "kind", SortKeyName: "seq"},
+			{IndexName: "seq-index", PartitionKeyName: "seq", PartitionKeyType: types.ScalarAttributeTypeN},
+		},
+	}
+	client := dynago.NewClientFromAPI(nil, opt)
+	for name, expected := range map[string]types.ScalarAttributeType{
+		"id":   types.ScalarAttributeTypeN,
+		"at":   types.ScalarAttributeTypeS,
+		"kind": types.ScalarAttributeTypeS,
+		"seq":  types.ScalarAttributeTypeN,
+	} {
+		if got, ok := client.KeyType(name); !ok || got != expected {
+			t.Errorf("expected key %s to be of type %s; got %s", name, expected, got)
+		}
+	}
+	cursor := map[string]dynago.Attribute{
+		"id":   dynago.NumberValue(1),
+		"at":   dynago.StringValue("2024"),
+		"kind": dynago.StringValue("login"),
+	}
+	if _, err := pagination.EncodeKeys(client, cursor); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	t.Run("conflicting types", func(t *testing.T) {
+		opt := opt
+		opt.Indexes = []dynago.Index{{IndexName: "id-index", PartitionKeyName: "id", PartitionKeyType: types.ScalarAttributeTypeS}}
+		_, err := dynago.NewClient(context.TODO(), opt)
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+		var logs bytes.Buffer
+		opt.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+		if got, _ := dynago.NewClientFromAPI(nil, opt).KeyType("id"); got != types.ScalarAttributeTypeN {
+			t.Errorf("expected table key type N to be kept; got %s", got)
+		}
+		if !strings.Contains(logs.String(), "invalid key types") {
+			t.Errorf("expected the conflict to be logged; got %q", logs.String())
+		}
+	})
+}
@@ -2,36 +2,97 @@ package dynago
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago/expression"
 )
 
-/**
-* Used to delete a db record from dynamodb given a partition key and sort key
-* @param pk the partition key of the record
-* @param sk the sort key of the record
- * @return true if the record was deleted, false otherwise
-*/
-func (t *Client) DeleteItem(ctx context.Context, pk string, sk string) error {
+type DeleteOption func(*dynamodb.DeleteItemInput) error
+
+// WithDeleteCondition only deletes the item if the condition expression evaluates to true.
+// A failed condition returns an error matching ErrConditionFailed
+func WithDeleteCondition(condition string, values map[string]Attribute) DeleteOption {
+	return func(input *dynamodb.DeleteItemInput) error {
+		input.ConditionExpression = &condition
+		if len(values) == 0 {
+			return nil
+		}
+		if input.ExpressionAttributeValues == nil {
+			input.ExpressionAttributeValues = map[string]Attribute{}
+		}
+		for k, v := range values {
+			if _, ok := input.ExpressionAttributeValues[k]; ok {
+				return fmt.Errorf("condition value %s is already set", k)
+			}
+			input.ExpressionAttributeValues[k] = v
+		}
+		return nil
+	}
+}
+
+// WithDeleteConditionExpr only deletes the item if a condition built by the expression package evaluates to true.
+// A failed condition returns an error matching ErrConditionFailed
+func WithDeleteConditionExpr(condition expression.Condition) DeleteOption {
+	return func(input *dynamodb.DeleteItemInput) error {
+		if condition.IsZero() {
+			return nil
+		}
+		expr, err := buildExpression(&input.ExpressionAttributeNames, &input.ExpressionAttributeValues, condition.Build)
+		if err != nil {
+			return err
+		}
+		input.ConditionExpression = aws.String(expr)
+		return nil
+	}
+}
+
+// WithDeleteReturnOldValues makes DeleteItem return the attributes of the deleted item
+func WithDeleteReturnOldValues() DeleteOption {
+	return func(input *dynamodb.DeleteItemInput) error {
+		input.ReturnValues = types.ReturnValueAllOld
+		return nil
+	}
+}
+
+// DeleteItem deletes the item with given partition key and sort key.
+// Deleting an item that does not exist succeeds; use WithDeleteCondition with attribute_exists to reject it.
+//
+// Returned attributes are empty unless WithDeleteReturnOldValues is used
+func (t *Client) DeleteItem(ctx context.Context, pk, sk Attribute, opts ...DeleteOption) (_ map[string]Attribute, err error) {
+	ctx, op := t.startOperation(ctx, "DeleteItem")
+	defer func() { op.end(err) }()
 
-	//delete item from dynamodb
 	input := &dynamodb.DeleteItemInput{
 		TableName: &t.TableName,
-		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: pk},
-			"sk": &types.AttributeValueMemberS{Value: sk},
-		},
+		Key:       t.NewKeys(pk, sk),
+	}
+	for _, opt := range opts {
+		if err := opt(input); err != nil {
+			return nil, err
+		}
 	}
-	resp, err := t.client.DeleteItem(ctx, input)
 
-	if err != nil && resp == nil {
-		log.Println("failed to delete record into database. Error:" + err.Error())
-		return err
+	resp, err := t.client.DeleteItem(ctx, input)
+	if err != nil {
+		t.log().ErrorContext(ctx, "failed to delete item", t.keyAttr(input.Key), slog.Any("error", err))
+		return nil, wrapError(err)
 	}
 
-	return nil
+	op.addItems(1)
+	return resp.Attributes, nil
+}
+
+// Delete deletes an item using the keys provided by the item, see ItemKeys
+func (t *Client) Delete(ctx context.Context, item interface{}, opts ...DeleteOption) (map[string]Attribute, error) {
+	pk, sk, err := t.itemKeys(item)
+	if err != nil {
+		return nil, err
+	}
+	return t.DeleteItem(ctx, pk, sk, opts...)
 }
 
 type TransactDeleteItemsInput struct {
@@ -39,21 +100,24 @@ type TransactDeleteItemsInput struct {
 	SortKeyValue      Attribute
 }
 
-// TODO: [low priority] The aggregate size of the items in the transaction cannot exceed 4 MB.
-func (t *Client) TransactDeleteItems(ctx context.Context, inputs []*TransactDeleteItemsInput) error {
+// TransactDeleteItems deletes up to 100 items in a single all-or-nothing operation.
+// Transactions canceled by conflicts or throttling are retried using ClientOptions.TransactionRetryPolicy
+func (t *Client) TransactDeleteItems(ctx context.Context, inputs []*TransactDeleteItemsInput) (err error) {
+	ctx, op := t.startOperation(ctx, "TransactDeleteItems")
+	defer func() { op.end(err) }()
+
 	requests := make([]types.TransactWriteItem, len(inputs))
 	for idx, in := range inputs {
 		requests[idx] = types.TransactWriteItem{
-			Delete: &types.Delete{TableName: &t.TableName,
-				Key: map[string]types.AttributeValue{
-					"pk": in.PartitionKeyValue,
-					"sk": in.SortKeyValue,
-				}},
+			Delete: &types.Delete{
+				TableName: &t.TableName,
+				Key:       t.NewKeys(in.PartitionKeyValue, in.SortKeyValue),
+			},
 		}
 	}
+	if err := checkTransaction(requests); err != nil {
+		return err
+	}
 
-	_, err := t.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: requests,
-	})
-	return err
+	return t.transactWriteItems(ctx, requests, newRequestToken())
 }
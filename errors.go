@@ -0,0 +1,192 @@
+package dynago
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	// ErrConditionFailed is matched by errors of writes rejected because a condition expression,
+	// eg: WithOptimisticLock, evaluated to false. Use errors.As with *ConditionFailedError to read the current item
+	ErrConditionFailed = errors.New("condition check failed")
+	// ErrThrottled is matched by errors of requests rejected because the table or account exceeded its throughput
+	ErrThrottled = errors.New("request throttled")
+	// ErrNotFound is matched by errors of requests to a table or index that does not exist
+	ErrNotFound = errors.New("resource not found")
+	// ErrValidation is matched by errors of requests rejected by DynamoDB as invalid, eg: malformed expressions or key types
+	ErrValidation = errors.New("validation failed")
+)
+
+// ConditionFailedError is returned when the condition expression of a write evaluated to false.
+// Item holds the current item when the request asked for it using ReturnValuesOnConditionCheckFailure
+type ConditionFailedError struct {
+	Item map[string]Attribute
+	Err  error
+}
+
+func (e *ConditionFailedError) Error() string {
+	return fmt.Sprintf("%s; %s", ErrConditionFailed, e.Err)
+}
+
+func (e *ConditionFailedError) Is(target error) bool {
+	return target == ErrConditionFailed
+}
+
+func (e *ConditionFailedError) Unwrap() error {
+	return e.Err
+}
+
+// CancellationReason describes why an item of a canceled transaction could not be written
+type CancellationReason struct {
+	// Position of the item in the transaction request
+	Index int
+	// DynamoDB reason code, eg: ConditionalCheckFailed, ThrottlingError or None when the item did not cause the cancellation
+	Code    string
+	Message string
+	// Current item, when requested using ReturnValuesOnConditionCheckFailure
+	Item map[string]Attribute
+}
+
+// TransactionCanceledError is returned when DynamoDB cancels a transaction.
+// Reasons has one entry per item of the transaction, in request order
+//
+//	var txErr *dynago.TransactionCanceledError
+//	if errors.As(err, &txErr) {
+//	  for _, reason := range txErr.Failed() {
+//	    log.Printf("item %d failed: %s", reason.Index, reason.Code)
+//	  }
+//	}
+type TransactionCanceledError struct {
+	Reasons []CancellationReason
+	// Number of times the transaction was sent, see ClientOptions.TransactionRetryPolicy
+	Attempts int
+	Err      error
+}
+
+func (e *TransactionCanceledError) Error() string {
+	failed := e.Failed()
+	details := make([]string, len(failed))
+	for i, r := range failed {
+		details[i] = fmt.Sprintf("item %d: %s", r.Index, r.Code)
+		if r.Message != "" {
+			details[i] += " (" + r.Message + ")"
+		}
+	}
+	if e.Attempts > 1 {
+		return fmt.Sprintf("transaction canceled after %d attempts; %s", e.Attempts, strings.Join(details, ", "))
+	}
+	return fmt.Sprintf("transaction canceled; %s", strings.Join(details, ", "))
+}
+
+// Retryable reports whether every item that caused the cancellation failed for a transient reason,
+// a conflict with another transaction or throttling, so sending the same transaction again may succeed
+func (e *TransactionCanceledError) Retryable() bool {
+	failed := e.Failed()
+	for _, r := range failed {
+		switch r.Code {
+		case "TransactionConflict", "ThrottlingError", "ProvisionedThroughputExceeded":
+		default:
+			return false
+		}
+	}
+	return len(failed) > 0
+}
+
+// Failed returns the reasons of the items that caused the transaction to be canceled
+func (e *TransactionCanceledError) Failed() []CancellationReason {
+	var failed []CancellationReason
+	for _, r := range e.Reasons {
+		if r.Code != "" && r.Code != "None" {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Is matches ErrConditionFailed, ErrThrottled or ErrValidation when an item of the transaction failed for that reason
+func (e *TransactionCanceledError) Is(target error) bool {
+	for _, r := range e.Reasons {
+		if reasonError(r.Code) == target && target != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *TransactionCanceledError) Unwrap() error {
+	return e.Err
+}
+
+func reasonError(code string) error {
+	switch code {
+	case "ConditionalCheckFailed":
+		return ErrConditionFailed
+	case "ThrottlingError", "ProvisionedThroughputExceeded":
+		return ErrThrottled
+	case "ValidationError":
+		return ErrValidation
+	}
+	return nil
+}
+
+// apiError associates an error returned by DynamoDB with one of the sentinel errors
+type apiError struct {
+	kind error
+	err  error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func (e *apiError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// wrapError maps errors returned by the DynamoDB API to ConditionFailedError, TransactionCanceledError or
+// an error matching one of the sentinel errors. The original error remains available through errors.As
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		reasons := make([]CancellationReason, len(canceled.CancellationReasons))
+		for i, r := range canceled.CancellationReasons {
+			reasons[i] = CancellationReason{
+				Index:   i,
+				Code:    aws.ToString(r.Code),
+				Message: aws.ToString(r.Message),
+				Item:    r.Item,
+			}
+		}
+		return &TransactionCanceledError{Reasons: reasons, Err: err}
+	}
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return &ConditionFailedError{Item: conditionFailed.Item, Err: err}
+	}
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.ErrorCode() {
+	case "ProvisionedThroughputExceededException", "ThrottlingException", "RequestLimitExceeded":
+		return &apiError{kind: ErrThrottled, err: err}
+	case "ResourceNotFoundException":
+		return &apiError{kind: ErrNotFound, err: err}
+	case "ValidationException":
+		return &apiError{kind: ErrValidation, err: err}
+	}
+	return err
+}
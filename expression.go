@@ -0,0 +1,24 @@
+package dynago
+
+import (
+	"fmt"
+	"maps"
+)
+
+// buildExpression builds an expression into copies of the attribute names and values of a request, so maps provided
+// by the caller are not modified. Empty maps are left nil as DynamoDB rejects them.
+// Expressions that can not be built return an ErrValidation and leave the maps unchanged
+func buildExpression(names *map[string]string, values *map[string]Attribute,
+	build func(map[string]string, map[string]Attribute) (string, map[string]string, map[string]Attribute, error)) (string, error) {
+	expr, n, v, err := build(maps.Clone(*names), maps.Clone(*values))
+	if err != nil {
+		return "", fmt.Errorf("%w; %w", ErrValidation, err)
+	}
+	if len(n) > 0 {
+		*names = n
+	}
+	if len(v) > 0 {
+		*values = v
+	}
+	return expr, nil
+}
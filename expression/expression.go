@@ -0,0 +1,354 @@
+// Package expression builds DynamoDB key condition, condition, filter and projection expressions.
+//
+// Attribute names and values are replaced by #name and :value placeholders automatically, so reserved words such as
+// Name, Status or Date can be used as attribute names. Placeholders are added to the attribute maps of the request
+// next to the ones already present, which lets several expressions share a request
+//
+//	key := expression.Key("pk").Equal(dynago.StringValue("user#1")).
+//	  And(expression.Key("sk").BeginsWith("order#"))
+//	filter := expression.Attr("Status").In(dynago.StringValue("paid"), dynago.StringValue("shipped")).
+//	  And(expression.Size("Items").GreaterThan(0))
+//
+//	table.Query(ctx, "", nil, &orders, dynago.WithKeyCondition(key), dynago.WithFilterExpr(filter))
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Condition is a condition or filter expression. The zero Condition is empty and ignored by And and Or
+type Condition struct {
+	build func(p *placeholders) string
+}
+
+// IsZero reports whether the condition is empty
+func (c Condition) IsZero() bool {
+	return c.build == nil
+}
+
+// Build returns the condition expression and the names and values maps with the placeholders it references added.
+// Placeholders already present in the maps are kept, names are reused when they refer to the same attribute.
+// Nil maps are allocated when a placeholder is added, so use the returned maps.
+// An error is returned for conditions DynamoDB would reject, eg: In without values
+func (c Condition) Build(names map[string]string, values map[string]types.AttributeValue) (string, map[string]string, map[string]types.AttributeValue, error) {
+	if c.build == nil {
+		return "", names, values, nil
+	}
+	p := &placeholders{names: names, values: values}
+	expr := c.build(p)
+	if p.err != nil {
+		return "", names, values, p.err
+	}
+	return expr, p.names, p.values, nil
+}
+
+// And is true when both c and every other condition are true
+func (c Condition) And(other ...Condition) Condition {
+	return And(append([]Condition{c}, other...)...)
+}
+
+// Or is true when c or any other condition is true
+func (c Condition) Or(other ...Condition) Condition {
+	return Or(append([]Condition{c}, other...)...)
+}
+
+// And is true when every condition is true
+func And(conditions ...Condition) Condition {
+	return join("AND", conditions)
+}
+
+// Or is true when any condition is true
+func Or(conditions ...Condition) Condition {
+	return join("OR", conditions)
+}
+
+// Not negates the condition
+func Not(c Condition) Condition {
+	if c.IsZero() {
+		return c
+	}
+	return Condition{func(p *placeholders) string {
+		return "NOT (" + c.build(p) + ")"
+	}}
+}
+
+func join(operator string, conditions []Condition) Condition {
+	var set []Condition
+	for _, c := range conditions {
+		if !c.IsZero() {
+			set = append(set, c)
+		}
+	}
+	switch len(set) {
+	case 0:
+		return Condition{}
+	case 1:
+		return set[0]
+	}
+	return Condition{func(p *placeholders) string {
+		parts := make([]string, len(set))
+		for i, c := range set {
+			parts[i] = "(" + c.build(p) + ")"
+		}
+		return strings.Join(parts, " "+operator+" ")
+	}}
+}
+
+// AttributeExists is true when the item has an attribute at path
+func AttributeExists(path string) Condition {
+	return function("attribute_exists", path)
+}
+
+// AttributeNotExists is true when the item has no attribute at path
+func AttributeNotExists(path string) Condition {
+	return function("attribute_not_exists", path)
+}
+
+// AttributeType is true when the attribute at path has the given type, eg: S, N, SS or M
+func AttributeType(path string, attributeType string) Condition {
+	return function("attribute_type", path, &types.AttributeValueMemberS{Value: attributeType})
+}
+
+func function(name, path string, args ...types.AttributeValue) Condition {
+	return Condition{func(p *placeholders) string {
+		operands := []string{p.path(path)}
+		for _, arg := range args {
+			operands = append(operands, p.value(arg))
+		}
+		return name + "(" + strings.Join(operands, ", ") + ")"
+	}}
+}
+
+// NameBuilder is an attribute of an item used in a condition. Nested attributes are separated by dots and list
+// elements are selected with [n], eg: Address.Lines[0]
+type NameBuilder struct {
+	path string
+}
+
+// Attr refers to the attribute at the given document path
+func Attr(path string) NameBuilder {
+	return NameBuilder{path: path}
+}
+
+// Equal is true when the attribute equals v
+func (n NameBuilder) Equal(v types.AttributeValue) Condition {
+	return compare(n.operand, "=", v)
+}
+
+// NotEqual is true when the attribute does not equal v, including when the attribute does not exist
+func (n NameBuilder) NotEqual(v types.AttributeValue) Condition {
+	return compare(n.operand, "<>", v)
+}
+
+// LessThan is true when the attribute is less than v
+func (n NameBuilder) LessThan(v types.AttributeValue) Condition {
+	return compare(n.operand, "<", v)
+}
+
+// LessThanEqual is true when the attribute is less than or equal to v
+func (n NameBuilder) LessThanEqual(v types.AttributeValue) Condition {
+	return compare(n.operand, "<=", v)
+}
+
+// GreaterThan is true when the attribute is greater than v
+func (n NameBuilder) GreaterThan(v types.AttributeValue) Condition {
+	return compare(n.operand, ">", v)
+}
+
+// GreaterThanEqual is true when the attribute is greater than or equal to v
+func (n NameBuilder) GreaterThanEqual(v types.AttributeValue) Condition {
+	return compare(n.operand, ">=", v)
+}
+
+// Between is true when the attribute is within low and high, inclusive
+func (n NameBuilder) Between(low, high types.AttributeValue) Condition {
+	return between(n.operand, low, high)
+}
+
+// In is true when the attribute equals any of the values. DynamoDB accepts from 1 up to 100 values,
+// Build returns an error without values
+func (n NameBuilder) In(values ...types.AttributeValue) Condition {
+	return Condition{func(p *placeholders) string {
+		if len(values) == 0 {
+			p.fail(fmt.Errorf("IN condition of %s has no values", n.path))
+		}
+		list := make([]string, len(values))
+		for i, v := range values {
+			list[i] = p.value(v)
+		}
+		return n.operand(p) + " IN (" + strings.Join(list, ", ") + ")"
+	}}
+}
+
+// BeginsWith is true when the string attribute starts with prefix
+func (n NameBuilder) BeginsWith(prefix string) Condition {
+	return function("begins_with", n.path, &types.AttributeValueMemberS{Value: prefix})
+}
+
+// Contains is true when the string attribute contains the substring v, or the set or list attribute contains the element v
+func (n NameBuilder) Contains(v types.AttributeValue) Condition {
+	return function("contains", n.path, v)
+}
+
+// Size refers to the size of the attribute, see Size
+func (n NameBuilder) Size() SizeBuilder {
+	return Size(n.path)
+}
+
+func (n NameBuilder) operand(p *placeholders) string {
+	return p.path(n.path)
+}
+
+// SizeBuilder is the size of an attribute used in a condition: the length of a string or binary and the number of
+// elements of a set, list or map
+type SizeBuilder struct {
+	path string
+}
+
+// Size refers to the size of the attribute at the given document path
+func Size(path string) SizeBuilder {
+	return SizeBuilder{path: path}
+}
+
+// Equal is true when the size equals n
+func (s SizeBuilder) Equal(n int) Condition {
+	return compare(s.operand, "=", number(n))
+}
+
+// NotEqual is true when the size does not equal n
+func (s SizeBuilder) NotEqual(n int) Condition {
+	return compare(s.operand, "<>", number(n))
+}
+
+// LessThan is true when the size is less than n
+func (s SizeBuilder) LessThan(n int) Condition {
+	return compare(s.operand, "<", number(n))
+}
+
+// LessThanEqual is true when the size is less than or equal to n
+func (s SizeBuilder) LessThanEqual(n int) Condition {
+	return compare(s.operand, "<=", number(n))
+}
+
+// GreaterThan is true when the size is greater than n
+func (s SizeBuilder) GreaterThan(n int) Condition {
+	return compare(s.operand, ">", number(n))
+}
+
+// GreaterThanEqual is true when the size is greater than or equal to n
+func (s SizeBuilder) GreaterThanEqual(n int) Condition {
+	return compare(s.operand, ">=", number(n))
+}
+
+// Between is true when the size is within low and high, inclusive
+func (s SizeBuilder) Between(low, high int) Condition {
+	return between(s.operand, number(low), number(high))
+}
+
+func (s SizeBuilder) operand(p *placeholders) string {
+	return "size(" + p.path(s.path) + ")"
+}
+
+func number(n int) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(n)}
+}
+
+func compare(operand func(*placeholders) string, operator string, v types.AttributeValue) Condition {
+	return Condition{func(p *placeholders) string {
+		return operand(p) + " " + operator + " " + p.value(v)
+	}}
+}
+
+func between(operand func(*placeholders) string, low, high types.AttributeValue) Condition {
+	return Condition{func(p *placeholders) string {
+		return operand(p) + " BETWEEN " + p.value(low) + " AND " + p.value(high)
+	}}
+}
+
+// ProjectionBuilder selects the attributes returned by a read
+type ProjectionBuilder struct {
+	paths []string
+}
+
+// Projection selects the attributes at the given document paths
+func Projection(paths ...string) ProjectionBuilder {
+	return ProjectionBuilder{paths: paths}
+}
+
+// Build returns the projection expression and the names map with the placeholders it references added.
+// A nil map is allocated when a placeholder is added, so use the returned map
+func (b ProjectionBuilder) Build(names map[string]string) (string, map[string]string) {
+	p := &placeholders{names: names}
+	list := make([]string, len(b.paths))
+	for i, path := range b.paths {
+		list[i] = p.path(path)
+	}
+	return strings.Join(list, ", "), p.names
+}
+
+// placeholders allocates the placeholders of an expression into the attribute maps of a request
+type placeholders struct {
+	names  map[string]string
+	values map[string]types.AttributeValue
+	// err is the first error found while building the expression
+	err error
+}
+
+// fail records err unless an error was already recorded
+func (p *placeholders) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// path returns the placeholder path for the given document path
+func (p *placeholders) path(path string) string {
+	segments := strings.Split(path, ".")
+	for idx, segment := range segments {
+		index := ""
+		if i := strings.IndexByte(segment, '['); i > 0 {
+			segment, index = segment[:i], segment[i:]
+		}
+		segments[idx] = p.name(segment) + index
+	}
+	return strings.Join(segments, ".")
+}
+
+// name returns the placeholder of an attribute name, reusing the placeholder of a known name
+func (p *placeholders) name(name string) string {
+	for k, v := range p.names {
+		if v == name {
+			return k
+		}
+	}
+	if p.names == nil {
+		p.names = map[string]string{}
+	}
+	placeholder := next(p.names, "#n")
+	p.names[placeholder] = name
+	return placeholder
+}
+
+// value returns a new placeholder for v
+func (p *placeholders) value(v types.AttributeValue) string {
+	if p.values == nil {
+		p.values = map[string]types.AttributeValue{}
+	}
+	placeholder := next(p.values, ":v")
+	p.values[placeholder] = v
+	return placeholder
+}
+
+// next returns the first placeholder with the given prefix that is not a key of m
+func next[V any](m map[string]V, prefix string) string {
+	for i := len(m); ; i++ {
+		placeholder := prefix + strconv.Itoa(i)
+		if _, ok := m[placeholder]; !ok {
+			return placeholder
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package expression_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago/expression"
+)
+
+func s(v string) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: v}
+}
+
+func n(v string) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: v}
+}
+
+func TestConditionBuild(t *testing.T) {
+	cases := []struct {
+		name      string
+		condition expression.Condition
+		expected  string
+		names     map[string]string
+		values    map[string]types.AttributeValue
+	}{
+		{
+			name:      "comparison",
+			condition: expression.Attr("Status").Equal(s("paid")),
+			expected:  "#n0 = :v0",
+			names:     map[string]string{"#n0": "Status"},
+			values:    map[string]types.AttributeValue{":v0": s("paid")},
+		},
+		{
+			name:      "in",
+			condition: expression.Attr("Status").In(s("paid"), s("shipped")),
+			expected:  "#n0 IN (:v0, :v1)",
+			names:     map[string]string{"#n0": "Status"},
+			values:    map[string]types.AttributeValue{":v0": s("paid"), ":v1": s("shipped")},
+		},
+		{
+			name:      "nested path and reused names",
+			condition: expression.Attr("Address.Lines[0]").BeginsWith("1").And(expression.AttributeExists("Address")),
+			expected:  "(begins_with(#n0.#n1[0], :v0)) AND (attribute_exists(#n0))",
+			names:     map[string]string{"#n0": "Address", "#n1": "Lines"},
+			values:    map[string]types.AttributeValue{":v0": s("1")},
+		},
+		{
+			name:      "size between",
+			condition: expression.Size("Items").Between(1, 10),
+			expected:  "size(#n0) BETWEEN :v0 AND :v1",
+			names:     map[string]string{"#n0": "Items"},
+			values:    map[string]types.AttributeValue{":v0": n("1"), ":v1": n("10")},
+		},
+		{
+			name:      "not and or skip empty conditions",
+			condition: expression.Or(expression.Condition{}, expression.Not(expression.AttributeNotExists("Date")), expression.Attr("Age").GreaterThan(n("18"))),
+			expected:  "(NOT (attribute_not_exists(#n0))) OR (#n1 > :v0)",
+			names:     map[string]string{"#n0": "Date", "#n1": "Age"},
+			values:    map[string]types.AttributeValue{":v0": n("18")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, names, values, err := tc.condition.Build(map[string]string{}, map[string]types.AttributeValue{})
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected expression %s; got %s", tc.expected, got)
+			}
+			if !reflect.DeepEqual(names, tc.names) {
+				t.Errorf("expected names %v; got %v", tc.names, names)
+			}
+			if !reflect.DeepEqual(values, tc.values) {
+				t.Errorf("expected values %v; got %v", tc.values, values)
+			}
+		})
+	}
+}
+
+func TestBuildKeepsExistingPlaceholders(t *testing.T) {
+	names := map[string]string{"#n0": "pk"}
+	values := map[string]types.AttributeValue{":v0": s("user#1"), ":v1": s("order#")}
+
+	key := expression.Key("pk").Equal(s("user#1")).And(expression.Key("sk").Between(s("a"), s("b")))
+	got, names, values := key.Build(names, values)
+	if want := "#n0 = :v2 AND #n1 BETWEEN :v3 AND :v4"; got != want {
+		t.Errorf("expected key condition %s; got %s", want, got)
+	}
+	if len(names) != 2 || names["#n1"] != "sk" || len(values) != 5 {
+		t.Errorf("expected placeholders to be added next to existing ones; got %v, %v", names, values)
+	}
+
+	if got, _ := expression.Projection("sk", "Name", "Meta.Date").Build(names); got != "#n1, #n2, #n3.#n4" {
+		t.Errorf("expected projection #n1, #n2, #n3.#n4; got %s", got)
+	}
+}
+
+func TestBuildNilMaps(t *testing.T) {
+	got, names, values, _ := expression.Attr("Name").Equal(s("Jon")).Build(nil, nil)
+	if got != "#n0 = :v0" || names["#n0"] != "Name" || !reflect.DeepEqual(values[":v0"], s("Jon")) {
+		t.Errorf("expected placeholders in allocated maps; got %s, %v, %v", got, names, values)
+	}
+
+	got, names, values = expression.Key("pk").Equal(s("user#1")).Build(nil, nil)
+	if got != "#n0 = :v0" || len(names) != 1 || len(values) != 1 {
+		t.Errorf("expected key condition placeholders in allocated maps; got %s, %v, %v", got, names, values)
+	}
+
+	got, names = expression.Projection("Name", "Meta.Date").Build(nil)
+	if got != "#n0, #n1.#n2" || len(names) != 3 {
+		t.Errorf("expected projection placeholders in allocated map; got %s, %v", got, names)
+	}
+
+	got, names, values, _ = expression.Condition{}.Build(nil, nil)
+	if got != "" || names != nil || values != nil {
+		t.Errorf("expected empty condition to leave maps nil; got %s, %v, %v", got, names, values)
+	}
+}
+
+func TestBuildEmptyIn(t *testing.T) {
+	condition := expression.Attr("Age").GreaterThan(n("18")).And(expression.Attr("Status").In())
+	got, _, _, err := condition.Build(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "Status") {
+		t.Fatalf("expected an error naming Status; got %v", err)
+	}
+	if got != "" {
+		t.Errorf("expected no expression; got %s", got)
+	}
+}
@@ -0,0 +1,94 @@
+package expression
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// KeyCondition is the key condition expression of a query: an equality condition on the partition key,
+// optionally combined with a condition on the sort key using And
+type KeyCondition struct {
+	build func(p *placeholders) string
+}
+
+// IsZero reports whether the key condition is empty
+func (k KeyCondition) IsZero() bool {
+	return k.build == nil
+}
+
+// Build returns the key condition expression and the names and values maps with the placeholders it references added.
+// Nil maps are allocated when a placeholder is added, so use the returned maps
+func (k KeyCondition) Build(names map[string]string, values map[string]types.AttributeValue) (string, map[string]string, map[string]types.AttributeValue) {
+	if k.build == nil {
+		return "", names, values
+	}
+	p := &placeholders{names: names, values: values}
+	return k.build(p), p.names, p.values
+}
+
+// And combines the partition key condition with a sort key condition
+func (k KeyCondition) And(other KeyCondition) KeyCondition {
+	if k.IsZero() {
+		return other
+	}
+	if other.IsZero() {
+		return k
+	}
+	return KeyCondition{func(p *placeholders) string {
+		return k.build(p) + " AND " + other.build(p)
+	}}
+}
+
+// KeyBuilder is a key attribute of a table or index used in a key condition
+type KeyBuilder struct {
+	name string
+}
+
+// Key refers to the key attribute with the given name. Unlike Attr the name is not a document path
+func Key(name string) KeyBuilder {
+	return KeyBuilder{name: name}
+}
+
+// Equal is true when the key equals v, the only condition allowed on a partition key
+func (k KeyBuilder) Equal(v types.AttributeValue) KeyCondition {
+	return k.compare("=", v)
+}
+
+// LessThan is true when the sort key is less than v
+func (k KeyBuilder) LessThan(v types.AttributeValue) KeyCondition {
+	return k.compare("<", v)
+}
+
+// LessThanEqual is true when the sort key is less than or equal to v
+func (k KeyBuilder) LessThanEqual(v types.AttributeValue) KeyCondition {
+	return k.compare("<=", v)
+}
+
+// GreaterThan is true when the sort key is greater than v
+func (k KeyBuilder) GreaterThan(v types.AttributeValue) KeyCondition {
+	return k.compare(">", v)
+}
+
+// GreaterThanEqual is true when the sort key is greater than or equal to v
+func (k KeyBuilder) GreaterThanEqual(v types.AttributeValue) KeyCondition {
+	return k.compare(">=", v)
+}
+
+// Between is true when the sort key is within low and high, inclusive
+func (k KeyBuilder) Between(low, high types.AttributeValue) KeyCondition {
+	return KeyCondition{func(p *placeholders) string {
+		return p.name(k.name) + " BETWEEN " + p.value(low) + " AND " + p.value(high)
+	}}
+}
+
+// BeginsWith is true when the string sort key starts with prefix
+func (k KeyBuilder) BeginsWith(prefix string) KeyCondition {
+	return KeyCondition{func(p *placeholders) string {
+		return "begins_with(" + p.name(k.name) + ", " + p.value(&types.AttributeValueMemberS{Value: prefix}) + ")"
+	}}
+}
+
+func (k KeyBuilder) compare(operator string, v types.AttributeValue) KeyCondition {
+	return KeyCondition{func(p *placeholders) string {
+		return p.name(k.name) + " " + operator + " " + p.value(v)
+	}}
+}
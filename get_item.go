@@ -2,7 +2,7 @@ package dynago
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -22,6 +22,9 @@ Used to get a db record from dynamodb given a partition key and sort key
 @return error, true if the record was found, false otherwise
 */
 func (t *Client) GetItem(ctx context.Context, pk Attribute, sk Attribute, out interface{}, opts ...GetItemOptions) (err error, found bool) {
+	ctx, op := t.startOperation(ctx, "GetItem")
+	defer func() { op.end(err) }()
+
 	input := &dynamodb.GetItemInput{
 		TableName: &t.TableName,
 		Key:       t.NewKeys(pk, sk),
@@ -36,21 +39,21 @@ func (t *Client) GetItem(ctx context.Context, pk Attribute, sk Attribute, out in
 
 	resp, err := t.client.GetItem(ctx, input)
 	if err != nil {
-		// fixme: remove logs or log based on log level
-		log.Println("failed to get record from database. Error:" + err.Error())
-		return err, false
+		t.log().ErrorContext(ctx, "failed to get item", t.keyAttr(input.Key), slog.Any("error", err))
+		return wrapError(err), false
 	}
 
 	if resp.Item == nil {
-		log.Printf("record not found %v %v\n", pk, sk)
+		t.log().DebugContext(ctx, "item not found", t.keyAttr(input.Key))
 		return nil, false
 	}
 
 	err = attributevalue.UnmarshalMap(resp.Item, &out)
 	if err != nil {
-		log.Println("unmarshal failed" + err.Error())
+		t.log().ErrorContext(ctx, "failed to unmarshal item", t.keyAttr(input.Key), slog.Any("error", err))
 		return err, true
 	}
 
+	op.addItems(1)
 	return nil, true
 }
@@ -21,17 +21,39 @@ func BoolValue(v bool) *types.AttributeValueMemberBOOL {
 	return &types.AttributeValueMemberBOOL{Value: v}
 }
 
+// StringSetValue creates a string set attribute, eg: for use with UpdateBuilder.Add and UpdateBuilder.Delete
+func StringSetValue(v ...string) *types.AttributeValueMemberSS {
+	return &types.AttributeValueMemberSS{Value: v}
+}
+
+// NumberSetValue creates a number set attribute, eg: for use with UpdateBuilder.Add and UpdateBuilder.Delete
+func NumberSetValue(v ...int64) *types.AttributeValueMemberNS {
+	values := make([]string, len(v))
+	for i, n := range v {
+		values[i] = strconv.FormatInt(n, 10)
+	}
+	return &types.AttributeValueMemberNS{Value: values}
+}
+
 type WriteAPI interface {
-	// Create or update given item in DynamoDB. Must implemenmt DynamoRecord interface.
-	// DynamoRecord.GetKeys will be called to get values for parition and sort keys.
+	// Create or replace given item in DynamoDB at the given partition and sort keys
 	PutItem(ctx context.Context, pk, sk Attribute, item interface{}, opt ...PutOption) error
-	DeleteItem(ctx context.Context, pk, sk string) error
-	BatchDeleteItems(ctx context.Context, input []AttributeRecord) []AttributeRecord
+	// Create or replace given item using keys provided by the item, which must implement Keyed or have dynago key tags
+	Put(ctx context.Context, item interface{}, opts ...PutOption) error
+	UpdateItem(ctx context.Context, pk, sk Attribute, update *UpdateBuilder, opts ...UpdateOption) (map[string]Attribute, error)
+	DeleteItem(ctx context.Context, pk, sk Attribute, opts ...DeleteOption) (map[string]Attribute, error)
+	Delete(ctx context.Context, item interface{}, opts ...DeleteOption) (map[string]Attribute, error)
+	BatchWriteItems(ctx context.Context, input []AttributeRecord) error
+	BatchDeleteItems(ctx context.Context, input []AttributeRecord) error
 }
 
 type TransactionAPI interface {
 	TransactPutItems(ctx context.Context, items []*TransactPutItemsInput) error
 	TransactItems(ctx context.Context, input ...types.TransactWriteItem) error
+	// Build a transaction with conditional put, update, delete and condition check items
+	NewTx() *Tx
+	// Read up to 100 items as a single serializable snapshot
+	TransactGetItems(ctx context.Context, inputs []*TransactGetItemsInput) ([]bool, error)
 }
 
 type ReadAPI interface {
@@ -44,6 +66,11 @@ type QueryAPI interface {
 	//
 	// If key condition contains template params eg: pk = :pk for values, second argument should provide values
 	Query(ctx context.Context, condition string, params map[string]Attribute, out interface{}, opts ...QueryOptions) (map[string]Attribute, error)
+	// Query up to pageSize items matching the filter expression, the cursor resumes right after the last item returned
+	QueryPage(ctx context.Context, condition string, params map[string]Attribute, pageSize int32, out interface{}, opts ...QueryOptions) (map[string]Attribute, error)
+	// Read every item in the table or index. Use ParallelScan to split large tables into concurrently scanned segments
+	Scan(ctx context.Context, out interface{}, opts ...ScanOptions) (map[string]Attribute, error)
+	ParallelScan(ctx context.Context, segments int32, cursors []map[string]Attribute, handler ScanHandler, opts ...ScanOptions) ([]map[string]Attribute, error)
 }
 
 type DynamoClient interface {
@@ -0,0 +1,186 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+// Keyed is implemented by items that provide their own partition and sort key values.
+// sk is ignored for tables without a sort key
+type Keyed interface {
+	GetKeys() (pk Attribute, sk Attribute)
+}
+
+// keyField describes how a key value is derived from a struct field or a key template
+type keyField struct {
+	field    int
+	template []templatePart
+}
+
+type templatePart struct {
+	literal string
+	// index of the struct field substituted in the template, -1 for literal parts
+	field int
+}
+
+type keyFields struct {
+	pk, sk *keyField
+}
+
+var keyFieldsCache sync.Map // map[reflect.Type]keyFieldsResult
+
+type keyFieldsResult struct {
+	fields keyFields
+	err    error
+}
+
+// ItemKeys returns the partition and sort key values of an item implementing Keyed or of a struct with `dynago` tags.
+//
+// A field tagged `dynago:"pk"` or `dynago:"sk"` provides the key value, string fields become string keys, numbers
+// number keys and byte slices binary keys. A key template builds a string key from other fields using their Go names
+//
+//	type User struct {
+//	  ID   string `dynago:"pk=USER#{ID},sk=PROFILE"`
+//	  Name string
+//	}
+func ItemKeys(item interface{}) (pk Attribute, sk Attribute, err error) {
+	if keyed, ok := item.(Keyed); ok {
+		pk, sk = keyed.GetKeys()
+		return pk, sk, nil
+	}
+
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil, fmt.Errorf("%w; cannot read keys of a nil item", ErrValidation)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("%w; item of type %T does not implement Keyed", ErrValidation, item)
+	}
+
+	fields, err := keyFieldsOf(v.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+	if fields.pk == nil {
+		return nil, nil, fmt.Errorf("%w; item of type %s has no field tagged dynago:\"pk\"", ErrValidation, v.Type())
+	}
+	if pk, err = fields.pk.value(v); err != nil {
+		return nil, nil, err
+	}
+	if fields.sk != nil {
+		if sk, err = fields.sk.value(v); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pk, sk, nil
+}
+
+// itemKeys returns the key values of an item, checking that a sort key is provided when the table has one
+func (t *Client) itemKeys(item interface{}) (pk Attribute, sk Attribute, err error) {
+	pk, sk, err = ItemKeys(item)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.Keys["sk"] != "" && sk == nil {
+		return nil, nil, fmt.Errorf("%w; item of type %T has no sort key", ErrValidation, item)
+	}
+	return pk, sk, nil
+}
+
+func (k *keyField) value(v reflect.Value) (Attribute, error) {
+	if k.template == nil {
+		return attributevalue.Marshal(v.Field(k.field).Interface())
+	}
+	var b strings.Builder
+	for _, part := range k.template {
+		if part.field < 0 {
+			b.WriteString(part.literal)
+			continue
+		}
+		fmt.Fprint(&b, v.Field(part.field).Interface())
+	}
+	return StringValue(b.String()), nil
+}
+
+func keyFieldsOf(typ reflect.Type) (keyFields, error) {
+	if cached, ok := keyFieldsCache.Load(typ); ok {
+		res := cached.(keyFieldsResult)
+		return res.fields, res.err
+	}
+	fields, err := parseKeyFields(typ)
+	keyFieldsCache.Store(typ, keyFieldsResult{fields: fields, err: err})
+	return fields, err
+}
+
+func parseKeyFields(typ reflect.Type) (keyFields, error) {
+	var fields keyFields
+	for i := 0; i < typ.NumField(); i++ {
+		tag, ok := typ.Field(i).Tag.Lookup("dynago")
+		if !ok {
+			continue
+		}
+		for _, entry := range strings.Split(tag, ",") {
+			name, template, hasTemplate := strings.Cut(strings.TrimSpace(entry), "=")
+			key := &keyField{field: i}
+			if !hasTemplate && !typ.Field(i).IsExported() {
+				return fields, fmt.Errorf("%w; key field %s.%s is not exported", ErrValidation, typ, typ.Field(i).Name)
+			}
+			if hasTemplate {
+				parts, err := parseTemplate(typ, template)
+				if err != nil {
+					return fields, err
+				}
+				key.template = parts
+			}
+
+			var target **keyField
+			switch name {
+			case "pk":
+				target = &fields.pk
+			case "sk":
+				target = &fields.sk
+			default:
+				return fields, fmt.Errorf("%w; unknown dynago tag %q on %s.%s", ErrValidation, entry, typ, typ.Field(i).Name)
+			}
+			if *target != nil {
+				return fields, fmt.Errorf("%w; %s has more than one %s tag", ErrValidation, typ, name)
+			}
+			*target = key
+		}
+	}
+	return fields, nil
+}
+
+// parseTemplate splits a key template such as USER#{ID} into literal parts and field references
+func parseTemplate(typ reflect.Type, template string) ([]templatePart, error) {
+	parts := []templatePart{}
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			parts = append(parts, templatePart{literal: template, field: -1})
+			break
+		}
+		if start > 0 {
+			parts = append(parts, templatePart{literal: template[:start], field: -1})
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w; key template of %s has an unterminated placeholder", ErrValidation, typ)
+		}
+		name := template[start+1 : start+end]
+		field, ok := typ.FieldByName(name)
+		if !ok || len(field.Index) != 1 || !field.IsExported() {
+			return nil, fmt.Errorf("%w; key template of %s refers to unknown field %s", ErrValidation, typ, name)
+		}
+		parts = append(parts, templatePart{field: field.Index[0]})
+		template = template[start+end+1:]
+	}
+	return parts, nil
+}
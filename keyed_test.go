@@ -0,0 +1,74 @@
+package dynago_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+type taggedUser struct {
+	ID   string `dynago:"pk=USER#{ID},sk=PROFILE"`
+	Name string
+}
+
+type taggedEvent struct {
+	Stream string `dynago:"pk"`
+	Seq    int64  `dynago:"sk=EVENT#{Stream}#{Seq}"`
+}
+
+type keyedOrder struct {
+	ID string
+}
+
+func (o keyedOrder) GetKeys() (dynago.Attribute, dynago.Attribute) {
+	return dynago.StringValue("ORDER#" + o.ID), dynago.StringValue("ORDER")
+}
+
+func TestItemKeys(t *testing.T) {
+	cases := []struct {
+		item        interface{}
+		pk, sk      dynago.Attribute
+		expectedErr bool
+	}{
+		{item: taggedUser{ID: "1"}, pk: dynago.StringValue("USER#1"), sk: dynago.StringValue("PROFILE")},
+		{item: &taggedUser{ID: "2"}, pk: dynago.StringValue("USER#2"), sk: dynago.StringValue("PROFILE")},
+		{item: taggedEvent{Stream: "s1", Seq: 7}, pk: dynago.StringValue("s1"), sk: dynago.StringValue("EVENT#s1#7")},
+		{item: keyedOrder{ID: "9"}, pk: dynago.StringValue("ORDER#9"), sk: dynago.StringValue("ORDER")},
+		{item: struct {
+			ID int64 `dynago:"pk"`
+		}{ID: 5}, pk: dynago.NumberValue(5)},
+		{item: struct{ ID string }{ID: "1"}, expectedErr: true},
+		{item: struct {
+			ID string `dynago:"pk=USER#{Missing}"`
+		}{}, expectedErr: true},
+		{item: struct {
+			ID string `dynago:"pk=USER#{ID"`
+		}{}, expectedErr: true},
+		{item: struct {
+			ID string `dynago:"pk,pk"`
+		}{}, expectedErr: true},
+		{item: "not a struct", expectedErr: true},
+		{item: (*taggedUser)(nil), expectedErr: true},
+	}
+
+	for idx, tc := range cases {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			pk, sk, err := dynago.ItemKeys(tc.item)
+			if tc.expectedErr {
+				if !errors.Is(err, dynago.ErrValidation) {
+					t.Errorf("expected ErrValidation; got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pk, tc.pk) || !reflect.DeepEqual(sk, tc.sk) {
+				t.Errorf("expected keys %v, %v; got %v, %v", tc.pk, tc.sk, pk, sk)
+			}
+		})
+	}
+}
@@ -1,16 +1,191 @@
 package dynago
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Index describes the key attributes of a global or local secondary index, register indexes with ClientOptions.Indexes.
+// SortKeyName is empty for indexes without a sort key and key types default to types.ScalarAttributeTypeS
 type Index struct {
 	IndexName        string
 	PartitionKeyName string
 	SortKeyName      string
+	PartitionKeyType types.ScalarAttributeType
+	SortKeyType      types.ScalarAttributeType
 }
 
 // Generate DynamoDB item key map for the given value
 // Name of the keys were registered during the NewDynamoTable call
+// sk is ignored for tables without a sort key
 func (t *Client) NewKeys(pk Attribute, sk Attribute) map[string]Attribute {
+	if t.Keys["sk"] == "" {
+		return map[string]Attribute{t.Keys["pk"]: pk}
+	}
 	return map[string]Attribute{
 		t.Keys["pk"]: pk,
 		t.Keys["sk"]: sk,
 	}
 }
+
+// KeyType returns the attribute type of a key attribute of the table or of a registered index
+func (t *Client) KeyType(name string) (types.ScalarAttributeType, bool) {
+	kt, ok := t.keyTypes[name]
+	return kt, ok
+}
+
+// keyTypesOf returns the attribute types of the keys of the table and its indexes. Table keys are registered first,
+// index keys reusing the name of another key keep its type unless they set a different type, which is an ErrValidation
+func keyTypesOf(opt ClientOptions) (map[string]types.ScalarAttributeType, error) {
+	keyTypes := map[string]types.ScalarAttributeType{}
+	var errs []error
+	add := func(name string, kt types.ScalarAttributeType) {
+		if err := addKeyType(keyTypes, name, kt); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	add(opt.PartitionKeyName, defaultKeyType(opt.PartitionKeyType))
+	add(opt.SortKeyName, defaultKeyType(opt.SortKeyType))
+	// index keys with a type are registered before defaulted ones so the order of indexes does not matter
+	for _, idx := range opt.Indexes {
+		add(idx.PartitionKeyName, idx.PartitionKeyType)
+		add(idx.SortKeyName, idx.SortKeyType)
+	}
+	for _, idx := range opt.Indexes {
+		add(idx.PartitionKeyName, defaultKeyType(idx.PartitionKeyType))
+		add(idx.SortKeyName, defaultKeyType(idx.SortKeyType))
+	}
+	return keyTypes, errors.Join(errs...)
+}
+
+// addKeyType registers the type of a key attribute, an empty type leaves the key unregistered
+func addKeyType(keyTypes map[string]types.ScalarAttributeType, name string, kt types.ScalarAttributeType) error {
+	if name == "" || kt == "" {
+		return nil
+	}
+	existing, ok := keyTypes[name]
+	if !ok {
+		keyTypes[name] = kt
+		return nil
+	}
+	if existing != kt {
+		return fmt.Errorf("%w; key attribute %s is registered with types %s and %s", ErrValidation, name, existing, kt)
+	}
+	return nil
+}
+
+// defaultKeyType returns the type of a key attribute, types.ScalarAttributeTypeS when not set
+func defaultKeyType(kt types.ScalarAttributeType) types.ScalarAttributeType {
+	if kt == "" {
+		return types.ScalarAttributeTypeS
+	}
+	return kt
+}
+
+// attributeType returns the scalar type of a key attribute value, or an empty type for other values
+func attributeType(v Attribute) types.ScalarAttributeType {
+	switch v.(type) {
+	case *types.AttributeValueMemberS:
+		return types.ScalarAttributeTypeS
+	case *types.AttributeValueMemberN:
+		return types.ScalarAttributeTypeN
+	case *types.AttributeValueMemberB:
+		return types.ScalarAttributeTypeB
+	}
+	return ""
+}
+
+// Generate DynamoDB key map of an item in the given secondary index.
+// Key names of the index must be registered using ClientOptions.Indexes
+func (t *Client) NewIndexKeys(index string, pk Attribute, sk Attribute) (map[string]Attribute, error) {
+	idx, ok := t.Indexes[index]
+	if !ok {
+		return nil, fmt.Errorf("%w; index %s is not registered in ClientOptions.Indexes", ErrValidation, index)
+	}
+	keys := map[string]Attribute{idx.PartitionKeyName: pk}
+	if idx.SortKeyName != "" {
+		keys[idx.SortKeyName] = sk
+	}
+	return keys, nil
+}
+
+// keyNames returns the attributes of a cursor for the table or, when reading a registered index,
+// the attributes of the table key and the index key
+func (t *Client) keyNames(index *string) []string {
+	var names []string
+	add := func(name string) {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	add(t.Keys["pk"])
+	add(t.Keys["sk"])
+	if index != nil {
+		if idx, ok := t.Indexes[*index]; ok {
+			add(idx.PartitionKeyName)
+			add(idx.SortKeyName)
+		}
+	}
+	return names
+}
+
+// validateCursor checks that a cursor holds every key attribute needed to resume reading the table or index.
+// Cursors of unregistered indexes are only checked for the table key
+func (t *Client) validateCursor(index *string, cursor map[string]Attribute) error {
+	if cursor == nil {
+		return nil
+	}
+	names := t.keyNames(index)
+	for _, name := range names {
+		v, ok := cursor[name]
+		if !ok {
+			return fmt.Errorf("%w; cursor is missing key attribute %s", ErrValidation, name)
+		}
+		if kt, ok := t.KeyType(name); ok && attributeType(v) != kt {
+			return fmt.Errorf("%w; cursor key attribute %s is not of type %s", ErrValidation, name, kt)
+		}
+	}
+	if index == nil || t.Indexes[*index].IndexName != "" {
+		for name := range cursor {
+			if !slices.Contains(names, name) {
+				return fmt.Errorf("%w; cursor has unexpected attribute %s", ErrValidation, name)
+			}
+		}
+	}
+	return nil
+}
+
+// keyString returns a stable string representation of a key map, used to detect duplicate keys
+func keyString(key map[string]Attribute) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for idx, name := range names {
+		if idx > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteString("=")
+		switch v := key[name].(type) {
+		case *types.AttributeValueMemberS:
+			b.WriteString("S:" + v.Value)
+		case *types.AttributeValueMemberN:
+			b.WriteString("N:" + v.Value)
+		case *types.AttributeValueMemberB:
+			b.WriteString("B:" + base64.StdEncoding.EncodeToString(v.Value))
+		default:
+			b.WriteString("?")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package dynago
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// redacted replaces key values in log records unless ClientOptions.LogKeyValues is set
+const redacted = "[REDACTED]"
+
+// discardHandler drops every record, it is used when ClientOptions.Logger is not set
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+var discardLogger = slog.New(discardHandler{})
+
+func newLogger(opt ClientOptions) *slog.Logger {
+	if opt.Logger == nil {
+		return discardLogger
+	}
+	return opt.Logger.With(slog.String("table", opt.TableName))
+}
+
+// log returns the logger of the client, a zero Client does not log
+func (t *Client) log() *slog.Logger {
+	if t.logger == nil {
+		return discardLogger
+	}
+	return t.logger
+}
+
+// keyAttr returns a log attribute holding the key attributes of an item.
+// Values are redacted unless ClientOptions.LogKeyValues is set
+func (t *Client) keyAttr(key map[string]Attribute) slog.Attr {
+	return slog.Any("key", keyValue{key: key, show: t.logKeyValues})
+}
+
+// keyValue formats keys only when a record is logged
+type keyValue struct {
+	key  map[string]Attribute
+	show bool
+}
+
+func (k keyValue) LogValue() slog.Value {
+	names := make([]string, 0, len(k.key))
+	for name := range k.key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]slog.Attr, len(names))
+	for i, name := range names {
+		value := redacted
+		if k.show {
+			value = attributeString(k.key[name])
+		}
+		attrs[i] = slog.String(name, value)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func attributeString(v Attribute) string {
+	switch v := v.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	case *types.AttributeValueMemberB:
+		return base64.StdEncoding.EncodeToString(v.Value)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,285 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+// binaryVersion is the first byte of every binary cursor
+const binaryVersion = 1
+
+// maxDepth is the deepest nesting of lists and maps accepted when decoding, the limit of DynamoDB documents
+const maxDepth = 32
+
+// type tags of the attributes of a binary cursor
+const (
+	tagS byte = iota + 1
+	tagN
+	tagB
+	tagBOOL
+	tagNULL
+	tagSS
+	tagNS
+	tagBS
+	tagL
+	tagM
+)
+
+// EncodeAttributes encodes the key of the last evaluated item into a compact URL safe cursor without a Key struct
+// or key schema. Every attribute type is kept as is, numbers keep their exact decimal form and binary values are
+// not re-encoded as text.
+//
+// Binary cursors hold a version, the query fingerprint when encoded WithQuery and the attributes sorted by name,
+// each as a type tag followed by its length prefixed value
+//
+//	cursor, err := pagination.EncodeAttributes(lastKey, pagination.WithQuery(query))
+func EncodeAttributes(attr map[string]dynago.Attribute, opts ...Option) (string, error) {
+	if attr == nil {
+		return "", nil
+	}
+	o := newOptions(opts)
+	buf := []byte{binaryVersion}
+	fingerprint := ""
+	if o.query != nil {
+		fingerprint = o.query.Fingerprint()
+	}
+	buf = appendString(buf, fingerprint)
+	buf, err := appendMap(buf, attr)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// DecodeAttributes decodes a cursor created by EncodeAttributes into the key of the last evaluated item.
+// Returns a *CursorError matching ErrCursorMalformed when the cursor can not be read
+func DecodeAttributes(encoded string, opts ...Option) (map[string]dynago.Attribute, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(data) == 0 {
+		return nil, &CursorError{Kind: ErrCursorMalformed}
+	}
+	if data[0] != binaryVersion {
+		return nil, &CursorError{Kind: ErrCursorVersion}
+	}
+	r := &reader{data: data[1:]}
+	fingerprint := r.string()
+	attr := r.attributes(0)
+	if r.err || len(r.data) > 0 {
+		return nil, &CursorError{Kind: ErrCursorMalformed}
+	}
+	if o := newOptions(opts); o.query != nil && fingerprint != o.query.Fingerprint() {
+		return nil, &CursorError{Kind: ErrCursorQueryMismatch}
+	}
+	return attr, nil
+}
+
+// EncodeAttributesSigned encodes a cursor using EncodeAttributes and signs it
+func EncodeAttributesSigned(s *Signer, attr map[string]dynago.Attribute, opts ...Option) (string, error) {
+	enc, err := EncodeAttributes(attr, opts...)
+	if err != nil {
+		return "", err
+	}
+	return s.Sign(enc)
+}
+
+// DecodeAttributesSigned verifies a cursor created by EncodeAttributesSigned and decodes it using DecodeAttributes
+func DecodeAttributesSigned(s *Signer, signed string, opts ...Option) (map[string]dynago.Attribute, error) {
+	enc, err := s.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeAttributes(enc, opts...)
+}
+
+func appendMap(buf []byte, attr map[string]dynago.Attribute) ([]byte, error) {
+	names := make([]string, 0, len(attr))
+	for name := range attr {
+		names = append(names, name)
+	}
+	// sorted names give the same cursor for the same key
+	slices.Sort(names)
+	buf = binary.AppendUvarint(buf, uint64(len(names)))
+	for _, name := range names {
+		var err error
+		buf = appendString(buf, name)
+		if buf, err = appendAttribute(buf, attr[name]); err != nil {
+			return nil, fmt.Errorf("cursor attribute %s; %w", name, err)
+		}
+	}
+	return buf, nil
+}
+
+func appendAttribute(buf []byte, v dynago.Attribute) ([]byte, error) {
+	switch v := v.(type) {
+	case *types.AttributeValueMemberS:
+		return appendString(append(buf, tagS), v.Value), nil
+	case *types.AttributeValueMemberN:
+		return appendString(append(buf, tagN), v.Value), nil
+	case *types.AttributeValueMemberB:
+		return appendBytes(append(buf, tagB), v.Value), nil
+	case *types.AttributeValueMemberBOOL:
+		if v.Value {
+			return append(buf, tagBOOL, 1), nil
+		}
+		return append(buf, tagBOOL, 0), nil
+	case *types.AttributeValueMemberNULL:
+		return append(buf, tagNULL), nil
+	case *types.AttributeValueMemberSS:
+		buf = binary.AppendUvarint(append(buf, tagSS), uint64(len(v.Value)))
+		for _, s := range v.Value {
+			buf = appendString(buf, s)
+		}
+		return buf, nil
+	case *types.AttributeValueMemberNS:
+		buf = binary.AppendUvarint(append(buf, tagNS), uint64(len(v.Value)))
+		for _, n := range v.Value {
+			buf = appendString(buf, n)
+		}
+		return buf, nil
+	case *types.AttributeValueMemberBS:
+		buf = binary.AppendUvarint(append(buf, tagBS), uint64(len(v.Value)))
+		for _, b := range v.Value {
+			buf = appendBytes(buf, b)
+		}
+		return buf, nil
+	case *types.AttributeValueMemberL:
+		buf = binary.AppendUvarint(append(buf, tagL), uint64(len(v.Value)))
+		for idx, item := range v.Value {
+			var err error
+			if buf, err = appendAttribute(buf, item); err != nil {
+				return nil, fmt.Errorf("list element %d; %w", idx, err)
+			}
+		}
+		return buf, nil
+	case *types.AttributeValueMemberM:
+		return appendMap(append(buf, tagM), v.Value)
+	}
+	return nil, fmt.Errorf("unsupported attribute type %T", v)
+}
+
+func appendString(buf []byte, s string) []byte {
+	return append(binary.AppendUvarint(buf, uint64(len(s))), s...)
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	return append(binary.AppendUvarint(buf, uint64(len(b))), b...)
+}
+
+// reader reads the attributes of a binary cursor. err is set by the first read past the end of the data,
+// later reads return zero values
+type reader struct {
+	data []byte
+	err  bool
+}
+
+func (r *reader) byte() byte {
+	if r.err || len(r.data) == 0 {
+		r.err = true
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+// length reads a length prefix. Every element takes at least one byte, so lengths over the remaining data are invalid
+func (r *reader) length() int {
+	if r.err {
+		return 0
+	}
+	n, size := binary.Uvarint(r.data)
+	if size <= 0 || n > uint64(len(r.data)-size) {
+		r.err = true
+		return 0
+	}
+	r.data = r.data[size:]
+	return int(n)
+}
+
+func (r *reader) bytes() []byte {
+	n := r.length()
+	if r.err {
+		return nil
+	}
+	b := slices.Clone(r.data[:n])
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) string() string {
+	return string(r.bytes())
+}
+
+func (r *reader) attributes(depth int) map[string]dynago.Attribute {
+	n := r.length()
+	attr := make(map[string]dynago.Attribute, n)
+	for range n {
+		name := r.string()
+		attr[name] = r.attribute(depth)
+		if r.err {
+			return nil
+		}
+	}
+	return attr
+}
+
+func (r *reader) attribute(depth int) dynago.Attribute {
+	switch tag := r.byte(); tag {
+	case tagS:
+		return &types.AttributeValueMemberS{Value: r.string()}
+	case tagN:
+		return &types.AttributeValueMemberN{Value: r.string()}
+	case tagB:
+		return &types.AttributeValueMemberB{Value: r.bytes()}
+	case tagBOOL:
+		switch r.byte() {
+		case 0:
+			return &types.AttributeValueMemberBOOL{Value: false}
+		case 1:
+			return &types.AttributeValueMemberBOOL{Value: true}
+		}
+	case tagNULL:
+		return &types.AttributeValueMemberNULL{Value: true}
+	case tagSS, tagNS, tagBS:
+		n := r.length()
+		if tag == tagBS {
+			set := make([][]byte, n)
+			for i := range set {
+				set[i] = r.bytes()
+			}
+			return &types.AttributeValueMemberBS{Value: set}
+		}
+		set := make([]string, n)
+		for i := range set {
+			set[i] = r.string()
+		}
+		if tag == tagNS {
+			return &types.AttributeValueMemberNS{Value: set}
+		}
+		return &types.AttributeValueMemberSS{Value: set}
+	case tagL:
+		if depth >= maxDepth {
+			break
+		}
+		list := make([]dynago.Attribute, r.length())
+		for i := range list {
+			list[i] = r.attribute(depth + 1)
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case tagM:
+		if depth >= maxDepth {
+			break
+		}
+		return &types.AttributeValueMemberM{Value: r.attributes(depth + 1)}
+	}
+	r.err = true
+	return nil
+}
@@ -0,0 +1,143 @@
+package pagination_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/pagination"
+)
+
+func TestBinaryCursor(t *testing.T) {
+	lastKey := map[string]dynago.Attribute{
+		"pk":        dynago.StringValue("tenant#1"),
+		"timestamp": dynago.NumberValue(1700000000123),
+		"price":     &types.AttributeValueMemberN{Value: "12.50"},
+		"hash":      &types.AttributeValueMemberB{Value: []byte{0, 1, 0xfe, 0xff}},
+		"active":    &types.AttributeValueMemberBOOL{Value: true},
+		"deleted":   &types.AttributeValueMemberNULL{Value: true},
+		"tags":      &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"sizes":     &types.AttributeValueMemberNS{Value: []string{"1", "1e3"}},
+		"blobs":     &types.AttributeValueMemberBS{Value: [][]byte{{1}, {}}},
+		"path": &types.AttributeValueMemberL{Value: []dynago.Attribute{
+			dynago.StringValue("x"),
+			&types.AttributeValueMemberM{Value: map[string]dynago.Attribute{"depth": dynago.NumberValue(2)}},
+		}},
+		"empty": &types.AttributeValueMemberM{Value: map[string]dynago.Attribute{}},
+	}
+	query := pagination.QueryOf("pk = :pk", dynago.SortByAsc(false))
+
+	t.Run("round trip", func(t *testing.T) {
+		cursor, err := pagination.EncodeAttributes(lastKey, pagination.WithQuery(query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.ContainsAny(cursor, "+/=") {
+			t.Errorf("expected URL safe cursor; got %s", cursor)
+		}
+		got, err := pagination.DecodeAttributes(cursor, pagination.WithQuery(query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, lastKey) {
+			t.Errorf("expected %v; got %v", lastKey, got)
+		}
+	})
+
+	t.Run("same key same cursor", func(t *testing.T) {
+		first, _ := pagination.EncodeAttributes(lastKey)
+		for range 10 {
+			if next, _ := pagination.EncodeAttributes(lastKey); next != first {
+				t.Fatalf("expected %s; got %s", first, next)
+			}
+		}
+	})
+
+	t.Run("smaller than json cursors", func(t *testing.T) {
+		table := dynago.NewClientFromAPI(nil, dynago.ClientOptions{TableName: "users", PartitionKeyName: "pk", SortKeyName: "sk"})
+		key := table.NewKeys(dynago.StringValue("tenant#1"), dynago.StringValue("user#1"))
+		binary, _ := pagination.EncodeAttributes(key)
+		json, _ := pagination.EncodeKeys(table, key)
+		if len(binary) >= len(json) {
+			t.Errorf("expected binary cursor %s to be shorter than %s", binary, json)
+		}
+	})
+
+	t.Run("nil and empty", func(t *testing.T) {
+		if cursor, err := pagination.EncodeAttributes(nil); cursor != "" || err != nil {
+			t.Errorf("expected empty cursor; got %q %v", cursor, err)
+		}
+		if key, err := pagination.DecodeAttributes(""); key != nil || err != nil {
+			t.Errorf("expected nil key; got %v %v", key, err)
+		}
+	})
+
+	t.Run("another query", func(t *testing.T) {
+		cursor, _ := pagination.EncodeAttributes(lastKey, pagination.WithQuery(query))
+		_, err := pagination.DecodeAttributes(cursor, pagination.WithQuery(pagination.QueryOf("pk = :pk")))
+		if !errors.Is(err, pagination.ErrCursorQueryMismatch) {
+			t.Errorf("expected ErrCursorQueryMismatch; got %v", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		cursor, _ := pagination.EncodeAttributes(lastKey)
+		data, _ := base64.RawURLEncoding.DecodeString(cursor)
+		nested := []byte{1, 0, 1, 1, 'a'}
+		for range 40 {
+			nested = append(nested, 9, 1) // list of one element
+		}
+		cases := map[string]string{
+			"not base64": "not a cursor!",
+			"truncated":  base64.RawURLEncoding.EncodeToString(data[:len(data)-3]),
+			"trailing":   base64.RawURLEncoding.EncodeToString(append(data, 0)),
+			"bad length": base64.RawURLEncoding.EncodeToString([]byte{1, 0, 0xff, 0xff, 0xff, 0xff, 0x0f}),
+			"bad type":   base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1, 1, 'a', 99}),
+			"too deep":   base64.RawURLEncoding.EncodeToString(append(nested, 5)),
+		}
+		for name, cursor := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := pagination.DecodeAttributes(cursor)
+				if !errors.Is(err, pagination.ErrCursorMalformed) {
+					t.Errorf("expected ErrCursorMalformed; got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("version", func(t *testing.T) {
+		_, err := pagination.DecodeAttributes(base64.RawURLEncoding.EncodeToString([]byte{9, 0, 0}))
+		if !errors.Is(err, pagination.ErrCursorVersion) {
+			t.Errorf("expected ErrCursorVersion; got %v", err)
+		}
+	})
+
+	t.Run("unsupported attribute", func(t *testing.T) {
+		_, err := pagination.EncodeAttributes(map[string]dynago.Attribute{"pk": nil})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		signer, err := pagination.NewSigner(pagination.SignerOptions{Keys: map[string][]byte{"k": []byte("secret")}, CurrentKey: "k", Encrypt: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cursor, err := pagination.EncodeAttributesSigned(signer, lastKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := pagination.DecodeAttributesSigned(signer, cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, lastKey) {
+			t.Errorf("expected %v; got %v", lastKey, got)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/oolio-group/dynago"
+)
+
+// ConnectionArgs are the arguments of a Relay style connection. Set First, optionally with After, to read forward
+// or Last, optionally with Before, to read the page before a cursor. Other combinations are rejected
+type ConnectionArgs struct {
+	First  int32
+	After  string
+	Last   int32
+	Before string
+}
+
+// Connection is a page of query results shaped for Relay style connections
+type Connection[T any] struct {
+	Edges    []Edge[T]
+	PageInfo PageInfo
+}
+
+// Edge is an item of a Connection with the cursor pointing at it
+type Edge[T any] struct {
+	Node   T
+	Cursor string
+}
+
+// PageInfo describes the position of a Connection in the query results.
+// The flag in the reading direction is exact. The other one is an estimate: reading forward HasPreviousPage is
+// set whenever After was given and reading backward HasNextPage whenever Before was given, even when no matching
+// items are left on that side of the cursor, as checking for them would need another request
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	// Cursors of the first and last edges, empty when there are no edges
+	StartCursor string
+	EndCursor   string
+}
+
+// Paginator reads pages of query results in both directions. The previous page is read by reversing the sort
+// order of the query from the first item of the current page, its items are returned in the original order.
+//
+// Cursors are bound to the query, see WithQuery, and use the key schema of the table and its registered indexes
+//
+//	users := pagination.NewPaginator(dynago.NewTable[User](client)).WithSigner(signer)
+//	page, err := users.Query(ctx, "pk = :pk", values, pagination.ConnectionArgs{First: 20})
+//	prev, err := users.Query(ctx, "pk = :pk", values, pagination.ConnectionArgs{Last: 20, Before: page.PageInfo.StartCursor})
+type Paginator[T any] struct {
+	table  *dynago.Table[T]
+	signer *Signer
+}
+
+// NewPaginator creates a Paginator of the queries of a table
+func NewPaginator[T any](table *dynago.Table[T]) *Paginator[T] {
+	return &Paginator[T]{table: table}
+}
+
+// WithSigner signs the cursors of the paginator, see Signer
+func (p *Paginator[T]) WithSigner(s *Signer) *Paginator[T] {
+	p.signer = s
+	return p
+}
+
+// Query returns the page of the query described by args. Options are applied to every request, a filter expression
+// only removes items from pages and pages always hold First or Last items unless the results run out
+func (p *Paginator[T]) Query(ctx context.Context, condition string, values map[string]dynago.Attribute, args ConnectionArgs, opts ...dynago.QueryOptions) (*Connection[T], error) {
+	backward := args.Last > 0
+	size := args.First
+	cursor := args.After
+	if backward {
+		size, cursor = args.Last, args.Before
+	}
+	if size < 1 || (args.First > 0 && backward) {
+		return nil, fmt.Errorf("%w; set either First or Last to a positive page size", dynago.ErrValidation)
+	}
+	if (backward && args.After != "") || (!backward && args.Before != "") {
+		return nil, fmt.Errorf("%w; After is used with First and Before with Last", dynago.ErrValidation)
+	}
+
+	client := p.table.Client()
+	query := QueryOf(condition, opts...)
+	keyNames, err := connectionKeys(client, query.IndexName)
+	if err != nil {
+		return nil, err
+	}
+	start, err := p.decode(client, cursor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(slices.Clone(opts), dynago.WithCursorKey(start))
+	if backward {
+		opts = append(opts, dynago.SortByAsc(query.Descending))
+	}
+	// one more item than the page size tells whether there is a page after this one
+	var items []map[string]dynago.Attribute
+	if _, err := client.QueryPage(ctx, condition, values, size+1, &items, opts...); err != nil {
+		return nil, err
+	}
+	more := len(items) > int(size)
+	if more {
+		items = items[:size]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+
+	conn := &Connection[T]{Edges: make([]Edge[T], len(items))}
+	for idx, item := range items {
+		if err := attributevalue.UnmarshalMap(item, &conn.Edges[idx].Node); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal item %d; %w", idx, err)
+		}
+		keys := make(map[string]dynago.Attribute, len(keyNames))
+		for _, name := range keyNames {
+			keys[name] = item[name]
+		}
+		if conn.Edges[idx].Cursor, err = p.encode(client, keys, query); err != nil {
+			return nil, err
+		}
+	}
+
+	if backward {
+		conn.PageInfo.HasPreviousPage, conn.PageInfo.HasNextPage = more, cursor != ""
+	} else {
+		conn.PageInfo.HasNextPage, conn.PageInfo.HasPreviousPage = more, cursor != ""
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn, nil
+}
+
+func (p *Paginator[T]) encode(client *dynago.Client, keys map[string]dynago.Attribute, query Query) (string, error) {
+	if p.signer != nil {
+		return EncodeKeysSigned(p.signer, client, keys, WithQuery(query))
+	}
+	return EncodeKeys(client, keys, WithQuery(query))
+}
+
+func (p *Paginator[T]) decode(client *dynago.Client, cursor string, query Query) (map[string]dynago.Attribute, error) {
+	if p.signer != nil {
+		return DecodeKeysSigned(p.signer, client, cursor, WithQuery(query))
+	}
+	return DecodeKeys(client, cursor, WithQuery(query))
+}
+
+// connectionKeys returns the names of the attributes needed to resume the query from an item
+func connectionKeys(client *dynago.Client, indexName string) ([]string, error) {
+	names := []string{client.Keys["pk"]}
+	if sk := client.Keys["sk"]; sk != "" {
+		names = append(names, sk)
+	}
+	if indexName == "" {
+		return names, nil
+	}
+	index, ok := client.Indexes[indexName]
+	if !ok {
+		return nil, fmt.Errorf("%w; index %s is not registered in ClientOptions.Indexes", dynago.ErrValidation, indexName)
+	}
+	for _, name := range []string{index.PartitionKeyName, index.SortKeyName} {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,170 @@
+package pagination_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/pagination"
+	"github.com/oolio-group/dynago/testing/memdb"
+)
+
+type member struct {
+	Pk   string `dynamodbav:"pk"`
+	Sk   string `dynamodbav:"sk"`
+	ID   string
+	City string
+}
+
+// countingAPI counts the Query requests sent to the wrapped API
+type countingAPI struct {
+	dynago.DynamoDBAPI
+	queries int
+}
+
+func (api *countingAPI) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	api.queries++
+	return api.DynamoDBAPI.Query(ctx, params, optFns...)
+}
+
+func TestPaginatorQuery(t *testing.T) {
+	ctx := context.TODO()
+	db := memdb.New()
+	if err := db.CreateTable(ctx, "members", "pk", "sk"); err != nil {
+		t.Fatal(err)
+	}
+	client := db.NewClient(dynago.ClientOptions{TableName: "members", PartitionKeyName: "pk", SortKeyName: "sk"})
+	for idx := range 10 {
+		m := member{
+			Pk:   "members",
+			Sk:   fmt.Sprintf("member#%02d", idx),
+			ID:   fmt.Sprintf("%d", idx),
+			City: []string{"Melbourne", "Perth"}[idx%2],
+		}
+		if err := client.PutItem(ctx, dynago.StringValue(m.Pk), dynago.StringValue(m.Sk), m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	members := pagination.NewPaginator(dynago.NewTable[member](client))
+	values := map[string]dynago.Attribute{":pk": dynago.StringValue("members")}
+
+	page := func(t *testing.T, values map[string]dynago.Attribute, args pagination.ConnectionArgs, opts ...dynago.QueryOptions) *pagination.Connection[member] {
+		t.Helper()
+		conn, err := members.Query(ctx, "pk = :pk", values, args, opts...)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return conn
+	}
+	expectPage := func(t *testing.T, conn *pagination.Connection[member], expected []string, hasPrevious, hasNext bool) {
+		t.Helper()
+		got := make([]string, len(conn.Edges))
+		for i, edge := range conn.Edges {
+			got[i] = edge.Node.ID
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected members %v; got %v", expected, got)
+		}
+		if conn.PageInfo.HasPreviousPage != hasPrevious || conn.PageInfo.HasNextPage != hasNext {
+			t.Errorf("expected previous %v and next %v; got %+v", hasPrevious, hasNext, conn.PageInfo)
+		}
+	}
+
+	t.Run("forward", func(t *testing.T) {
+		first := page(t, values, pagination.ConnectionArgs{First: 4})
+		expectPage(t, first, []string{"0", "1", "2", "3"}, false, true)
+		second := page(t, values, pagination.ConnectionArgs{First: 4, After: first.PageInfo.EndCursor})
+		expectPage(t, second, []string{"4", "5", "6", "7"}, true, true)
+		third := page(t, values, pagination.ConnectionArgs{First: 4, After: second.PageInfo.EndCursor})
+		expectPage(t, third, []string{"8", "9"}, true, false)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		last := page(t, values, pagination.ConnectionArgs{Last: 4})
+		expectPage(t, last, []string{"6", "7", "8", "9"}, true, false)
+		prev := page(t, values, pagination.ConnectionArgs{Last: 4, Before: last.PageInfo.StartCursor})
+		expectPage(t, prev, []string{"2", "3", "4", "5"}, true, true)
+		prev = page(t, values, pagination.ConnectionArgs{Last: 4, Before: prev.PageInfo.StartCursor})
+		expectPage(t, prev, []string{"0", "1"}, false, true)
+	})
+
+	t.Run("filtered pages", func(t *testing.T) {
+		filter := dynago.WithFilter("City = :city")
+		filterValues := map[string]dynago.Attribute{
+			":pk":   dynago.StringValue("members"),
+			":city": dynago.StringValue("Perth"),
+		}
+		first := page(t, filterValues, pagination.ConnectionArgs{First: 2}, filter)
+		expectPage(t, first, []string{"1", "3"}, false, true)
+		second := page(t, filterValues, pagination.ConnectionArgs{First: 2, After: first.PageInfo.EndCursor}, filter)
+		expectPage(t, second, []string{"5", "7"}, true, true)
+		prev := page(t, filterValues, pagination.ConnectionArgs{Last: 2, Before: second.PageInfo.StartCursor}, filter)
+		expectPage(t, prev, []string{"1", "3"}, false, true)
+	})
+
+	t.Run("cursor of the other direction is rejected", func(t *testing.T) {
+		first := page(t, values, pagination.ConnectionArgs{First: 2})
+		cases := map[string]pagination.ConnectionArgs{
+			"after with last":   {Last: 2, After: first.PageInfo.EndCursor},
+			"before with first": {First: 2, Before: first.PageInfo.EndCursor},
+		}
+		for name, args := range cases {
+			if _, err := members.Query(ctx, "pk = :pk", values, args); !errors.Is(err, dynago.ErrValidation) {
+				t.Errorf("%s: expected ErrValidation; got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestPaginatorRequests(t *testing.T) {
+	ctx := context.TODO()
+	db := memdb.New()
+	if err := db.CreateTable(ctx, "members", "pk", "sk"); err != nil {
+		t.Fatal(err)
+	}
+	api := &countingAPI{DynamoDBAPI: db}
+	client := dynago.NewClientFromAPI(api, dynago.ClientOptions{TableName: "members", PartitionKeyName: "pk", SortKeyName: "sk"})
+	items := make([]map[string]dynago.Attribute, 1000)
+	for idx := range items {
+		city := "Melbourne"
+		if idx%100 == 0 {
+			city = "Perth"
+		}
+		items[idx] = map[string]dynago.Attribute{
+			"pk":   dynago.StringValue("members"),
+			"sk":   dynago.StringValue(fmt.Sprintf("member#%04d", idx)),
+			"ID":   dynago.StringValue(fmt.Sprint(idx)),
+			"City": dynago.StringValue(city),
+		}
+	}
+	if err := client.BatchWriteItems(ctx, items); err != nil {
+		t.Fatal(err)
+	}
+	members := pagination.NewPaginator(dynago.NewTable[member](client))
+	values := map[string]dynago.Attribute{":pk": dynago.StringValue("members"), ":city": dynago.StringValue("Perth")}
+	filter := dynago.WithFilter("City = :city")
+
+	// a selective filter does not make requests evaluate fewer items, each page is read with a single request
+	query := func(t *testing.T, args pagination.ConnectionArgs) *pagination.Connection[member] {
+		t.Helper()
+		api.queries = 0
+		conn, err := members.Query(ctx, "pk = :pk", values, args, filter)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(conn.Edges) != 5 || api.queries != 1 {
+			t.Errorf("expected a page of 5 members read with 1 request; got %d members with %d requests", len(conn.Edges), api.queries)
+		}
+		return conn
+	}
+	first := query(t, pagination.ConnectionArgs{First: 5})
+	second := query(t, pagination.ConnectionArgs{First: 5, After: first.PageInfo.EndCursor})
+	if second.PageInfo.HasNextPage || !second.PageInfo.HasPreviousPage {
+		t.Errorf("expected the last page; got %+v", second.PageInfo)
+	}
+	query(t, pagination.ConnectionArgs{Last: 5, Before: second.PageInfo.StartCursor})
+}
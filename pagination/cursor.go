@@ -4,26 +4,44 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"github.com/oolio-group/dynago"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
-func Decode[Key any](encoded string) (map[string]dynago.Attribute, error) {
+// CursorVersion is the format version of the cursors created by this package
+const CursorVersion = 1
+
+// envelope is the versioned form of a cursor. Cursors without a version are the JSON form of their key
+type envelope struct {
+	Version     int             `json:"_v"`
+	Fingerprint string          `json:"_q,omitempty"`
+	Key         json.RawMessage `json:"_k"`
+}
+
+// Decode decodes a cursor created by Encode into the key of the last evaluated item
+func Decode[Key any](encoded string, opts ...Option) (map[string]dynago.Attribute, error) {
 	if encoded == "" {
 		return nil, nil
 	}
 
+	raw, err := decodeCursor(encoded, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
 	var dec Key
-	if err := decodeFromBase64(&dec, encoded); err != nil {
+	if err := json.Unmarshal(raw, &dec); err != nil {
 		return nil, err
 	}
 	out, err := attributevalue.MarshalMap(&dec)
 	return out, err
 }
 
-func Encode[Key any](attr map[string]dynago.Attribute) (string, error) {
+// Encode encodes the key of the last evaluated item using the fields of the Key struct
+func Encode[Key any](attr map[string]dynago.Attribute, opts ...Option) (string, error) {
 	if attr == nil {
 		return "", nil
 	}
@@ -34,7 +52,7 @@ func Encode[Key any](attr map[string]dynago.Attribute) (string, error) {
 		return "", err
 	}
 
-	enc, err := encodeToBase64(&k)
+	enc, err := encodeCursor(&k, newOptions(opts))
 	if err != nil {
 		return "", err
 	}
@@ -42,6 +60,117 @@ func Encode[Key any](attr map[string]dynago.Attribute) (string, error) {
 	return enc, nil
 }
 
+// EncodeKeys encodes a cursor using the key schema registered in the table client instead of a Key struct.
+// Works with tables without sort key and with string, number or binary keys of the table and its registered indexes
+func EncodeKeys(table *dynago.Client, attr map[string]dynago.Attribute, opts ...Option) (string, error) {
+	if attr == nil {
+		return "", nil
+	}
+
+	values := make(map[string]string, len(attr))
+	for name, v := range attr {
+		kt, ok := table.KeyType(name)
+		if !ok {
+			return "", fmt.Errorf("cursor attribute %s is not a key of the table or a registered index", name)
+		}
+		switch v := v.(type) {
+		case *types.AttributeValueMemberS:
+			if kt == types.ScalarAttributeTypeS {
+				values[name] = v.Value
+				continue
+			}
+		case *types.AttributeValueMemberN:
+			if kt == types.ScalarAttributeTypeN {
+				values[name] = v.Value
+				continue
+			}
+		case *types.AttributeValueMemberB:
+			if kt == types.ScalarAttributeTypeB {
+				values[name] = base64.StdEncoding.EncodeToString(v.Value)
+				continue
+			}
+		}
+		return "", fmt.Errorf("cursor attribute %s is not of type %s", name, kt)
+	}
+	return encodeCursor(values, newOptions(opts))
+}
+
+// DecodeKeys decodes a cursor created by EncodeKeys using the key schema registered in the table client
+func DecodeKeys(table *dynago.Client, encoded string, opts ...Option) (map[string]dynago.Attribute, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	raw, err := decodeCursor(encoded, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	out := make(map[string]dynago.Attribute, len(values))
+	for name, v := range values {
+		kt, ok := table.KeyType(name)
+		if !ok {
+			return nil, fmt.Errorf("cursor attribute %s is not a key of the table or a registered index", name)
+		}
+		switch kt {
+		case types.ScalarAttributeTypeN:
+			out[name] = &types.AttributeValueMemberN{Value: v}
+		case types.ScalarAttributeTypeB:
+			b, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return nil, fmt.Errorf("cursor attribute %s is not valid binary; %w", name, err)
+			}
+			out[name] = &types.AttributeValueMemberB{Value: b}
+		default:
+			out[name] = &types.AttributeValueMemberS{Value: v}
+		}
+	}
+	return out, nil
+}
+
+// encodeCursor encodes the key of a cursor in a versioned envelope, with the fingerprint of the query when bound to one
+func encodeCursor(key interface{}, o options) (string, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	env := envelope{Version: CursorVersion, Key: raw}
+	if o.query != nil {
+		env.Fingerprint = o.query.Fingerprint()
+	}
+	return encodeToBase64(env)
+}
+
+// decodeCursor returns the JSON form of the key of a cursor after checking its version and query
+func decodeCursor(encoded string, o options) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := decodeFromBase64(&raw, encoded); err != nil {
+		return nil, err
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, err
+	}
+	switch env.Version {
+	case 0:
+		// cursors created before cursors had a version hold the key only, they can not be checked against a query
+		if o.query != nil {
+			return nil, &CursorError{Kind: ErrCursorQueryMismatch}
+		}
+		return raw, nil
+	case CursorVersion:
+	default:
+		return nil, &CursorError{Kind: ErrCursorVersion}
+	}
+	if o.query != nil && env.Fingerprint != o.query.Fingerprint() {
+		return nil, &CursorError{Kind: ErrCursorQueryMismatch}
+	}
+	return env.Key, nil
+}
+
 func encodeToBase64(v interface{}) (string, error) {
 	var buf bytes.Buffer
 	encoder := base64.NewEncoder(base64.StdEncoding, &buf)
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 type TestKey struct {
@@ -30,7 +31,7 @@ func TestEncode(t *testing.T) {
 				"timePk":    dynago.StringValue("not_a-number"),
 				"timestamp": dynago.NumberValue(999999999),
 			},
-			expected: "eyJQayI6InNvbWUjdmFsdWUiLCJTayI6ImFub3RoZXJfdmFsdWUiLCJUaW1lc3RhbXAiOjk5OTk5OTk5OSwiVGltZXNlcmllcyI6Im5vdF9hLW51bWJlciJ9Cg==",
+			expected: "eyJfdiI6MSwiX2siOnsiUGsiOiJzb21lI3ZhbHVlIiwiU2siOiJhbm90aGVyX3ZhbHVlIiwiVGltZXN0YW1wIjo5OTk5OTk5OTksIlRpbWVzZXJpZXMiOiJub3RfYS1udW1iZXIifX0K",
 		},
 		{
 			input: map[string]dynago.Attribute{
@@ -43,7 +44,7 @@ func TestEncode(t *testing.T) {
 		},
 		{
 			input:    map[string]dynago.Attribute{},
-			expected: "eyJQayI6IiIsIlNrIjoiIiwiVGltZXN0YW1wIjowLCJUaW1lc2VyaWVzIjoiIn0K",
+			expected: "eyJfdiI6MSwiX2siOnsiUGsiOiIiLCJTayI6IiIsIlRpbWVzdGFtcCI6MCwiVGltZXNlcmllcyI6IiJ9fQo=",
 		},
 		{
 			input:    nil,
@@ -80,8 +81,18 @@ func TestDecode(t *testing.T) {
 				Timeseries: "not_a-number",
 				Timestamp:  999999999,
 			},
+			// unversioned cursor
 			input: "eyJQayI6InNvbWUjdmFsdWUiLCJTayI6ImFub3RoZXJfdmFsdWUiLCJUaW1lc3RhbXAiOjk5OTk5OTk5OSwiVGltZXNlcmllcyI6Im5vdF9hLW51bWJlciJ9Cg==",
 		},
+		{
+			expected: TestKey{
+				Pk:         "some#value",
+				Sk:         "another_value",
+				Timeseries: "not_a-number",
+				Timestamp:  999999999,
+			},
+			input: "eyJfdiI6MSwiX2siOnsiUGsiOiJzb21lI3ZhbHVlIiwiU2siOiJhbm90aGVyX3ZhbHVlIiwiVGltZXN0YW1wIjo5OTk5OTk5OTksIlRpbWVzZXJpZXMiOiJub3RfYS1udW1iZXIifX0K",
+		},
 		{
 			input:    "",
 			expected: TestKey{},
@@ -149,3 +160,63 @@ func TestEncodeDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeDecodeKeys(t *testing.T) {
+	hashOnly := dynago.NewClientFromAPI(nil, dynago.ClientOptions{
+		TableName:        "devices",
+		PartitionKeyName: "id",
+		PartitionKeyType: types.ScalarAttributeTypeN,
+	})
+	composite := dynago.NewClientFromAPI(nil, dynago.ClientOptions{
+		TableName:        "blobs",
+		PartitionKeyName: "owner",
+		SortKeyName:      "hash",
+		SortKeyType:      types.ScalarAttributeTypeB,
+		Indexes: []dynago.Index{
+			{IndexName: "by-size", PartitionKeyName: "owner", SortKeyName: "size", SortKeyType: types.ScalarAttributeTypeN},
+		},
+	})
+
+	cases := []struct {
+		table       *dynago.Client
+		input       map[string]dynago.Attribute
+		expectedErr bool
+	}{
+		{table: hashOnly, input: hashOnly.NewKeys(dynago.NumberValue(42), nil)},
+		{table: hashOnly, input: nil},
+		{table: composite, input: composite.NewKeys(dynago.StringValue("user#1"), &types.AttributeValueMemberB{Value: []byte{0, 1, 255}})},
+		{
+			table: composite,
+			input: map[string]dynago.Attribute{
+				"owner": dynago.StringValue("user#1"),
+				"hash":  &types.AttributeValueMemberB{Value: []byte{0, 1, 255}},
+				"size":  dynago.NumberValue(1024),
+			},
+		},
+		{table: hashOnly, input: map[string]dynago.Attribute{"id": dynago.StringValue("42")}, expectedErr: true},
+		{table: hashOnly, input: map[string]dynago.Attribute{"other": dynago.NumberValue(1)}, expectedErr: true},
+	}
+
+	for idx, tc := range cases {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			enc, err := pagination.EncodeKeys(tc.table, tc.input)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("expected error; got %s", enc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dec, err := pagination.DecodeKeys(tc.table, enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dec, tc.input) {
+				t.Errorf("expected %v got %v", tc.input, dec)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrCursorTampered is matched by errors of cursors that were modified, truncated or not signed by a Signer
+	ErrCursorTampered = errors.New("cursor signature is invalid")
+	// ErrCursorExpired is matched by errors of cursors older than SignerOptions.TTL
+	ErrCursorExpired = errors.New("cursor expired")
+	// ErrCursorUnknownKey is matched by errors of cursors signed with a key that is not in SignerOptions.Keys
+	ErrCursorUnknownKey = errors.New("cursor signed with unknown key")
+	// ErrCursorQueryMismatch is matched by errors of cursors decoded for another query than the one they were encoded for
+	ErrCursorQueryMismatch = errors.New("cursor belongs to another query")
+	// ErrCursorVersion is matched by errors of cursors with a format version this package does not support
+	ErrCursorVersion = errors.New("unsupported cursor version")
+	// ErrCursorMalformed is matched by errors of binary cursors that are truncated or hold invalid attributes
+	ErrCursorMalformed = errors.New("cursor is malformed")
+)
+
+// CursorError is returned when a cursor is rejected. It matches one of the ErrCursor errors
+type CursorError struct {
+	Kind error
+	// Id of the key the cursor claims to be signed with, empty when the cursor could not be read
+	KeyID string
+	// Time the cursor was signed at, zero when the cursor could not be read
+	SignedAt time.Time
+}
+
+func (e *CursorError) Error() string {
+	if e.KeyID != "" {
+		return fmt.Sprintf("%s; key %s", e.Kind, e.KeyID)
+	}
+	return e.Kind.Error()
+}
+
+func (e *CursorError) Is(target error) bool {
+	return target == e.Kind
+}
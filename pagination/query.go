@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/oolio-group/dynago"
+)
+
+// Query identifies the query a cursor resumes. A cursor encoded WithQuery is rejected when decoded for another query
+type Query struct {
+	KeyCondition string
+	IndexName    string
+	// Descending is set for queries reading the sort key in descending order, see dynago.SortByAsc
+	Descending bool
+	Filter     string
+	// Names are the ExpressionAttributeNames of the query, the attributes #name placeholders of the expressions refer to
+	Names map[string]string
+}
+
+// QueryOf returns the Query of a Client.Query call with the given key condition and options
+//
+//	q := pagination.QueryOf("pk = :pk", dynago.WithIndex("city-index"), dynago.SortByAsc(false))
+func QueryOf(condition string, opts ...dynago.QueryOptions) Query {
+	input := &dynamodb.QueryInput{KeyConditionExpression: aws.String(condition)}
+	for _, opt := range opts {
+		opt(input)
+	}
+	return Query{
+		KeyCondition: aws.ToString(input.KeyConditionExpression),
+		IndexName:    aws.ToString(input.IndexName),
+		Descending:   input.ScanIndexForward != nil && !*input.ScanIndexForward,
+		Filter:       aws.ToString(input.FilterExpression),
+		Names:        input.ExpressionAttributeNames,
+	}
+}
+
+// Fingerprint returns a short hash of the query stored in cursors encoded WithQuery.
+// It covers the text of the key condition and filter and the attribute names of their placeholders, not the :values
+// they reference, so a cursor of one partition matches the same query of any other partition. A Signer only rejects
+// modified cursors, check the partition key of the decoded keys to keep cursors within a partition or tenant
+func (q Query) Fingerprint() string {
+	direction := "asc"
+	if q.Descending {
+		direction = "desc"
+	}
+	parts := []string{q.KeyCondition, q.IndexName, direction, q.Filter}
+	// names are only hashed when present, so fingerprints of queries without placeholders stay the same
+	for _, placeholder := range slices.Sorted(maps.Keys(q.Names)) {
+		parts = append(parts, placeholder+"="+q.Names[placeholder])
+	}
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+// Option configures how cursors are encoded and decoded
+type Option func(*options)
+
+type options struct {
+	query *Query
+}
+
+// WithQuery binds cursors to a query. Encoding stores the fingerprint of the query in the cursor and decoding fails
+// with ErrCursorQueryMismatch when the cursor was encoded for another query or without a query.
+// Cursors encoded before cursors had a format version are only accepted when decoding without WithQuery
+func WithQuery(q Query) Option {
+	return func(o *options) {
+		o.query = &q
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
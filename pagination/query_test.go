@@ -0,0 +1,96 @@
+package pagination_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/expression"
+	"github.com/oolio-group/dynago/pagination"
+)
+
+func TestQueryBoundCursor(t *testing.T) {
+	table := dynago.NewClientFromAPI(nil, dynago.ClientOptions{TableName: "users", PartitionKeyName: "pk", SortKeyName: "sk"})
+	lastKey := table.NewKeys(dynago.StringValue("users"), dynago.StringValue("user#1"))
+	query := pagination.QueryOf("pk = :pk", dynago.WithFilter("Age > :age"))
+
+	cursor, err := pagination.EncodeKeys(table, lastKey, pagination.WithQuery(query))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := pagination.DecodeKeys(table, cursor, pagination.WithQuery(pagination.QueryOf("pk = :pk", dynago.WithFilter("Age > :age"))))
+	if err != nil || !reflect.DeepEqual(got, lastKey) {
+		t.Errorf("expected cursor of the same query to decode; got %v, %v", got, err)
+	}
+	if _, err := pagination.DecodeKeys(table, cursor); err != nil {
+		t.Errorf("expected cursor to decode without a query; got %v", err)
+	}
+
+	others := map[string]pagination.Query{
+		"condition": pagination.QueryOf("pk = :other", dynago.WithFilter("Age > :age")),
+		"index":     pagination.QueryOf("pk = :pk", dynago.WithFilter("Age > :age"), dynago.WithIndex("city-index")),
+		"direction": pagination.QueryOf("pk = :pk", dynago.WithFilter("Age > :age"), dynago.SortByAsc(false)),
+		"filter":    pagination.QueryOf("pk = :pk"),
+	}
+	for name, other := range others {
+		t.Run(name, func(t *testing.T) {
+			_, err := pagination.DecodeKeys(table, cursor, pagination.WithQuery(other))
+			if !errors.Is(err, pagination.ErrCursorQueryMismatch) {
+				t.Errorf("expected ErrCursorQueryMismatch; got %v", err)
+			}
+		})
+	}
+
+	t.Run("unbound cursor", func(t *testing.T) {
+		unbound, _ := pagination.EncodeKeys(table, lastKey)
+		_, err := pagination.DecodeKeys(table, unbound, pagination.WithQuery(query))
+		if !errors.Is(err, pagination.ErrCursorQueryMismatch) {
+			t.Errorf("expected ErrCursorQueryMismatch; got %v", err)
+		}
+	})
+
+	t.Run("unversioned cursor", func(t *testing.T) {
+		legacy := base64.StdEncoding.EncodeToString([]byte(`{"pk":"users","sk":"user#1"}`))
+		got, err := pagination.DecodeKeys(table, legacy)
+		if err != nil || !reflect.DeepEqual(got, lastKey) {
+			t.Errorf("expected unversioned cursor to decode; got %v, %v", got, err)
+		}
+		_, err = pagination.DecodeKeys(table, legacy, pagination.WithQuery(query))
+		if !errors.Is(err, pagination.ErrCursorQueryMismatch) {
+			t.Errorf("expected unversioned cursor to be rejected for a query; got %v", err)
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		future := base64.StdEncoding.EncodeToString([]byte(`{"_v":99,"_k":{"pk":"users","sk":"user#1"}}`))
+		_, err := pagination.DecodeKeys(table, future)
+		if !errors.Is(err, pagination.ErrCursorVersion) {
+			t.Errorf("expected ErrCursorVersion; got %v", err)
+		}
+	})
+}
+
+func TestQueryFingerprintNames(t *testing.T) {
+	table := dynago.NewClientFromAPI(nil, dynago.ClientOptions{TableName: "users", PartitionKeyName: "pk", SortKeyName: "sk"})
+	lastKey := table.NewKeys(dynago.StringValue("users"), dynago.StringValue("user#1"))
+	filter := func(name string) pagination.Query {
+		return pagination.QueryOf("pk = :pk", dynago.WithFilterExpr(expression.Attr(name).Equal(dynago.StringValue("active"))))
+	}
+	status, owner := filter("Status"), filter("Owner")
+	if status.Filter != owner.Filter {
+		t.Fatalf("expected filters with the same placeholders; got %s and %s", status.Filter, owner.Filter)
+	}
+	if status.Fingerprint() == owner.Fingerprint() || status.Fingerprint() != filter("Status").Fingerprint() {
+		t.Errorf("expected fingerprints to differ by attribute name only")
+	}
+
+	cursor, err := pagination.EncodeKeys(table, lastKey, pagination.WithQuery(status))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pagination.DecodeKeys(table, cursor, pagination.WithQuery(owner)); !errors.Is(err, pagination.ErrCursorQueryMismatch) {
+		t.Errorf("expected ErrCursorQueryMismatch; got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package pagination
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/oolio-group/dynago"
+)
+
+// SignerOptions configures the keys of a Signer
+type SignerOptions struct {
+	// Secrets by key id, cursors signed with any of them are accepted.
+	// Keep retired keys until the cursors they signed expire to rotate keys without breaking clients
+	Keys map[string][]byte
+	// Id of the key new cursors are signed with, must be in Keys
+	CurrentKey string
+	// Encrypt cursors with AES-GCM so clients can not read the keys of the last item
+	Encrypt bool
+	// Cursors older than TTL are rejected, cursors never expire when not set
+	TTL time.Duration
+	// Clock used to sign cursors and check their age, time.Now when not set
+	Now func() time.Time
+}
+
+// Signer signs cursors with HMAC-SHA256, and optionally encrypts them, so API clients can not edit a cursor to
+// read items of another partition. Signed cursors are URL safe
+//
+//	signer, err := pagination.NewSigner(pagination.SignerOptions{
+//	  Keys:       map[string][]byte{"2024-06": secret, "2024-01": oldSecret},
+//	  CurrentKey: "2024-06",
+//	  TTL:        24 * time.Hour,
+//	})
+//	cursor, err := pagination.EncodeKeysSigned(signer, table, lastKey)
+//	lastKey, err = pagination.DecodeKeysSigned(signer, table, cursor)
+type Signer struct {
+	keys    map[string]signerKey
+	current string
+	encrypt bool
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type signerKey struct {
+	mac    []byte
+	cipher cipher.AEAD
+}
+
+// signedVersion is the first byte of every signed cursor
+const signedVersion = 1
+
+const flagEncrypted = 1
+
+// NewSigner creates a Signer. Returns an error when no key is configured or CurrentKey is not one of Keys
+func NewSigner(opt SignerOptions) (*Signer, error) {
+	if _, ok := opt.Keys[opt.CurrentKey]; !ok {
+		return nil, fmt.Errorf("current key %q is not one of the signer keys", opt.CurrentKey)
+	}
+	s := &Signer{
+		keys:    make(map[string]signerKey, len(opt.Keys)),
+		current: opt.CurrentKey,
+		encrypt: opt.Encrypt,
+		ttl:     opt.TTL,
+		now:     opt.Now,
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	for id, secret := range opt.Keys {
+		if len(id) > 255 {
+			return nil, fmt.Errorf("key id %q is longer than 255 bytes", id)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("key %q is empty", id)
+		}
+		// separate keys are derived for signing and encryption so a secret of any length can be used
+		block, err := aes.NewCipher(deriveKey(secret, "dynago cursor encryption"))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		s.keys[id] = signerKey{mac: deriveKey(secret, "dynago cursor signature"), cipher: aead}
+	}
+	return s, nil
+}
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// Sign signs an encoded cursor, eg: returned by Encode or EncodeKeys. An empty cursor stays empty.
+//
+// Signed cursors hold a version, flags, the key id, the signing time, the cursor (encrypted when
+// SignerOptions.Encrypt is set) and the HMAC of all of them
+func (s *Signer) Sign(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	key := s.keys[s.current]
+	var buf bytes.Buffer
+	buf.WriteByte(signedVersion)
+	payload := []byte(cursor)
+	if s.encrypt {
+		buf.WriteByte(flagEncrypted)
+		nonce := make([]byte, key.cipher.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		payload = key.cipher.Seal(nonce, nonce, payload, []byte(s.current))
+	} else {
+		buf.WriteByte(0)
+	}
+	buf.WriteByte(byte(len(s.current)))
+	buf.WriteString(s.current)
+	buf.Write(binary.BigEndian.AppendUint64(nil, uint64(s.now().Unix())))
+	buf.Write(payload)
+
+	mac := hmac.New(sha256.New, key.mac)
+	mac.Write(buf.Bytes())
+	buf.Write(mac.Sum(nil))
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Verify checks the signature and age of a cursor created by Sign and returns the encoded cursor.
+// Returns a *CursorError when the cursor was modified, signed with an unknown key or expired
+func (s *Signer) Verify(signed string) (string, error) {
+	if signed == "" {
+		return "", nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(signed)
+	if err != nil || len(data) < 3 || data[0] != signedVersion {
+		return "", &CursorError{Kind: ErrCursorTampered}
+	}
+	flags, idLen := data[1], int(data[2])
+	if len(data) < 3+idLen+8+sha256.Size {
+		return "", &CursorError{Kind: ErrCursorTampered}
+	}
+	id := string(data[3 : 3+idLen])
+	key, ok := s.keys[id]
+	if !ok {
+		return "", &CursorError{Kind: ErrCursorUnknownKey, KeyID: id}
+	}
+
+	body, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	mac := hmac.New(sha256.New, key.mac)
+	mac.Write(body)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return "", &CursorError{Kind: ErrCursorTampered, KeyID: id}
+	}
+
+	signedAt := time.Unix(int64(binary.BigEndian.Uint64(body[3+idLen:])), 0)
+	if s.ttl > 0 && s.now().Sub(signedAt) > s.ttl {
+		return "", &CursorError{Kind: ErrCursorExpired, KeyID: id, SignedAt: signedAt}
+	}
+
+	payload := body[3+idLen+8:]
+	if flags&flagEncrypted != 0 {
+		size := key.cipher.NonceSize()
+		if len(payload) < size {
+			return "", &CursorError{Kind: ErrCursorTampered, KeyID: id}
+		}
+		payload, err = key.cipher.Open(nil, payload[:size], payload[size:], []byte(id))
+		if err != nil {
+			return "", &CursorError{Kind: ErrCursorTampered, KeyID: id}
+		}
+	}
+	return string(payload), nil
+}
+
+// EncodeSigned encodes a cursor using Encode and signs it
+func EncodeSigned[Key any](s *Signer, attr map[string]dynago.Attribute, opts ...Option) (string, error) {
+	enc, err := Encode[Key](attr, opts...)
+	if err != nil {
+		return "", err
+	}
+	return s.Sign(enc)
+}
+
+// DecodeSigned verifies a cursor created by EncodeSigned and decodes it using Decode
+func DecodeSigned[Key any](s *Signer, signed string, opts ...Option) (map[string]dynago.Attribute, error) {
+	enc, err := s.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+	return Decode[Key](enc, opts...)
+}
+
+// EncodeKeysSigned encodes a cursor using EncodeKeys and signs it
+func EncodeKeysSigned(s *Signer, table *dynago.Client, attr map[string]dynago.Attribute, opts ...Option) (string, error) {
+	enc, err := EncodeKeys(table, attr, opts...)
+	if err != nil {
+		return "", err
+	}
+	return s.Sign(enc)
+}
+
+// DecodeKeysSigned verifies a cursor created by EncodeKeysSigned and decodes it using DecodeKeys
+func DecodeKeysSigned(s *Signer, table *dynago.Client, signed string, opts ...Option) (map[string]dynago.Attribute, error) {
+	enc, err := s.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeKeys(table, enc, opts...)
+}
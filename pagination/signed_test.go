@@ -0,0 +1,107 @@
+package pagination_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/pagination"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	keys := map[string][]byte{"old": []byte("old secret"), "new": []byte("new secret")}
+	table := dynago.NewClientFromAPI(nil, dynago.ClientOptions{TableName: "users", PartitionKeyName: "pk", SortKeyName: "sk"})
+	lastKey := table.NewKeys(dynago.StringValue("tenant#1"), dynago.StringValue("user#1"))
+
+	newSigner := func(t *testing.T, opt pagination.SignerOptions) *pagination.Signer {
+		t.Helper()
+		opt.Now = clock
+		s, err := pagination.NewSigner(opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	expectCursorError := func(t *testing.T, err, kind error) {
+		t.Helper()
+		var cursorErr *pagination.CursorError
+		if !errors.As(err, &cursorErr) || !errors.Is(err, kind) {
+			t.Errorf("expected %s; got %v", kind, err)
+		}
+	}
+
+	for _, encrypt := range []bool{false, true} {
+		signer := newSigner(t, pagination.SignerOptions{Keys: keys, CurrentKey: "new", Encrypt: encrypt, TTL: time.Hour})
+		signed, err := pagination.EncodeKeysSigned(signer, table, lastKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.ContainsAny(signed, "+/=") {
+			t.Errorf("expected URL safe cursor; got %s", signed)
+		}
+		got, err := pagination.DecodeKeysSigned(signer, table, signed)
+		if err != nil || !reflect.DeepEqual(got, lastKey) {
+			t.Errorf("expected cursor to round trip; got %v, %v", got, err)
+		}
+
+		plain, _ := pagination.EncodeKeys(table, lastKey)
+		verified, _ := signer.Verify(signed)
+		if verified != plain {
+			t.Errorf("expected verified cursor %s; got %s", plain, verified)
+		}
+	}
+
+	signer := newSigner(t, pagination.SignerOptions{Keys: keys, CurrentKey: "new", TTL: time.Hour})
+	signed, err := pagination.EncodeKeysSigned(signer, table, lastKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("tampered cursor", func(t *testing.T) {
+		forged, _ := pagination.EncodeKeys(table, table.NewKeys(dynago.StringValue("tenant#2"), dynago.StringValue("user#1")))
+		_, err := signer.Verify(strings.Replace(signed, signed[len(signed)-50:len(signed)-45], "AAAAA", 1))
+		expectCursorError(t, err, pagination.ErrCursorTampered)
+		_, err = pagination.DecodeKeysSigned(signer, table, forged)
+		expectCursorError(t, err, pagination.ErrCursorTampered)
+	})
+
+	t.Run("rotated keys", func(t *testing.T) {
+		old := newSigner(t, pagination.SignerOptions{Keys: keys, CurrentKey: "old"})
+		signed, _ := old.Sign("cursor")
+		if got, err := signer.Verify(signed); err != nil || got != "cursor" {
+			t.Errorf("expected cursor signed with a retired key to be accepted; got %s, %v", got, err)
+		}
+
+		retired := newSigner(t, pagination.SignerOptions{Keys: map[string][]byte{"new": keys["new"]}, CurrentKey: "new"})
+		_, err := retired.Verify(signed)
+		expectCursorError(t, err, pagination.ErrCursorUnknownKey)
+
+		foreign := newSigner(t, pagination.SignerOptions{Keys: map[string][]byte{"old": []byte("other secret")}, CurrentKey: "old"})
+		_, err = foreign.Verify(signed)
+		expectCursorError(t, err, pagination.ErrCursorTampered)
+	})
+
+	t.Run("expired cursor", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		defer func() { now = now.Add(-2 * time.Hour) }()
+		_, err := signer.Verify(signed)
+		expectCursorError(t, err, pagination.ErrCursorExpired)
+	})
+
+	t.Run("empty cursor", func(t *testing.T) {
+		if got, err := signer.Verify(""); got != "" || err != nil {
+			t.Errorf("expected empty cursor; got %s, %v", got, err)
+		}
+	})
+
+	t.Run("current key must exist", func(t *testing.T) {
+		if _, err := pagination.NewSigner(pagination.SignerOptions{Keys: keys, CurrentKey: "missing"}); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
@@ -3,11 +3,13 @@ package dynago
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago/expression"
 )
 
 type PutOption func(*dynamodb.PutItemInput) error
@@ -18,6 +20,7 @@ type PutOption func(*dynamodb.PutItemInput) error
 // Provide key field acts as a version number (Usually called Version)
 // GetItem retrieves current version number and you can update the item if the version number in DynamoDB hasn't changed
 // Each update increments the version number and if the update fails fetch the record again to get latest version number and try again
+// A version conflict returns an error matching ErrConditionFailed
 func WithOptimisticLock(key string, currentVersion uint) PutOption {
 	return func(input *dynamodb.PutItemInput) error {
 		// Ensure the condition expression is set to check if the version attribute does not exist or matches the old version
@@ -36,15 +39,36 @@ func WithOptimisticLock(key string, currentVersion uint) PutOption {
 	}
 }
 
+// WithPutConditionExpr only puts the item if a condition built by the expression package evaluates to true.
+// A failed condition returns an error matching ErrConditionFailed
+//
+//	table.PutItem(ctx, pk, sk, item, dynago.WithPutConditionExpr(expression.AttributeNotExists("pk")))
+func WithPutConditionExpr(condition expression.Condition) PutOption {
+	return func(input *dynamodb.PutItemInput) error {
+		if condition.IsZero() {
+			return nil
+		}
+		expr, err := buildExpression(&input.ExpressionAttributeNames, &input.ExpressionAttributeValues, condition.Build)
+		if err != nil {
+			return err
+		}
+		input.ConditionExpression = aws.String(expr)
+		return nil
+	}
+}
+
 /**
 * Used to put and update a db record from dynamodb given a partition key and sort key
 * @param item the item put into the database
  * @return true if the record was put, false otherwise
 */
-func (t *Client) PutItem(ctx context.Context, pk, sk Attribute, item interface{}, opts ...PutOption) error {
+func (t *Client) PutItem(ctx context.Context, pk, sk Attribute, item interface{}, opts ...PutOption) (err error) {
+	ctx, op := t.startOperation(ctx, "PutItem")
+	defer func() { op.end(err) }()
+
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
-		log.Println("Failed to Marshal item" + err.Error())
+		t.log().ErrorContext(ctx, "failed to marshal item", t.keyAttr(t.NewKeys(pk, sk)), slog.Any("error", err))
 		return err
 	}
 
@@ -56,22 +80,34 @@ func (t *Client) PutItem(ctx context.Context, pk, sk Attribute, item interface{}
 		TableName: &t.TableName,
 		Item:      av,
 	}
-	// Apply option functions
-	if len(opts) > 0 {
-		for _, opt := range opts {
-			opt(input)
+	for _, opt := range opts {
+		if err := opt(input); err != nil {
+			return err
 		}
 	}
+	if err := checkItemSize(0, input.Item); err != nil {
+		return err
+	}
 
 	_, err = t.client.PutItem(ctx, input)
 	if err != nil {
-		log.Println("Failed to Put item" + err.Error())
-		return err
+		t.log().ErrorContext(ctx, "failed to put item", t.keyAttr(t.NewKeys(pk, sk)), slog.Any("error", err))
+		return wrapError(err)
 	}
 
+	op.addItems(1)
 	return nil
 }
 
+// Put creates or replaces an item using the keys provided by the item, see ItemKeys
+func (t *Client) Put(ctx context.Context, item interface{}, opts ...PutOption) error {
+	pk, sk, err := t.itemKeys(item)
+	if err != nil {
+		return err
+	}
+	return t.PutItem(ctx, pk, sk, item, opts...)
+}
+
 type TransactPutItemsInput struct {
 	PartitionKeyValue Attribute
 	SortKeyValue      Attribute
@@ -80,9 +116,13 @@ type TransactPutItemsInput struct {
 
 // TransactWriteItems is a synchronous and idempotent write operation that groups up to 100 write actions in a single all-or-nothing operation.
 // These actions can target up to 100 distinct items in one or more DynamoDB tables within the same AWS account and in the same Region.
-// The aggregate size of the items in the transaction cannot exceed 4 MB.
+// The aggregate size of the items in the transaction cannot exceed 4 MB, larger transactions are rejected before sending.
 // The actions are completed atomically so that either all of them succeed or none of them succeeds.
-func (t *Client) TransactPutItems(ctx context.Context, inputs []*TransactPutItemsInput) error {
+// Transactions canceled by conflicts or throttling are retried using ClientOptions.TransactionRetryPolicy.
+func (t *Client) TransactPutItems(ctx context.Context, inputs []*TransactPutItemsInput) (err error) {
+	ctx, op := t.startOperation(ctx, "TransactPutItems")
+	defer func() { op.end(err) }()
+
 	requests := make([]types.TransactWriteItem, len(inputs))
 	for idx, in := range inputs {
 		item, err := attributevalue.MarshalMap(in.Item)
@@ -98,8 +138,8 @@ func (t *Client) TransactPutItems(ctx context.Context, inputs []*TransactPutItem
 			Put: &types.Put{Item: item, TableName: &t.TableName},
 		}
 	}
-	_, err := t.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: requests,
-	})
-	return err
+	if err := checkTransaction(requests); err != nil {
+		return err
+	}
+	return t.transactWriteItems(ctx, requests, newRequestToken())
 }
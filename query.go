@@ -2,12 +2,14 @@ package dynago
 
 import (
 	"context"
-	"log"
-	"strings"
+	"fmt"
+	"log/slog"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/oolio-group/dynago/expression"
 )
 
 type QueryInput = dynamodb.QueryInput
@@ -15,11 +17,43 @@ type QueryInput = dynamodb.QueryInput
 // Fuction Struct for providing option input prams
 type QueryOptions func(q *dynamodb.QueryInput)
 
-// WithFields func to be passed as optional param function to select specfic fielld from the db enity
+// queryErrors holds the first error of the options applied to a query input by applyQueryOptions, as QueryOptions
+// can not return errors. Errors of options applied to other inputs are dropped
+var queryErrors sync.Map
+
+// failQuery records err as the error of the options applied to q unless an error was already recorded
+func failQuery(q *dynamodb.QueryInput, err error) {
+	if v, ok := queryErrors.Load(q); ok && *v.(*error) == nil {
+		*v.(*error) = err
+	}
+}
+
+// applyQueryOptions applies opts to input and returns the first error of an option that failed to build an expression
+func applyQueryOptions(input *dynamodb.QueryInput, opts []QueryOptions) error {
+	var err error
+	queryErrors.Store(input, &err)
+	defer queryErrors.Delete(input)
+	for _, opt := range opts {
+		opt(input)
+	}
+	return err
+}
+
+// WithFields func to be passed as optional param function to select specfic fielld from the db enity.
+// Field names are escaped using placeholders, nested attributes are separated by dots
 func WithFields(fields []string) QueryOptions {
-	exp := aws.String(strings.Join(fields, ", "))
+	projection := expression.Projection(fields...)
 	return func(q *dynamodb.QueryInput) {
-		q.ProjectionExpression = exp
+		expr, err := buildExpression(&q.ExpressionAttributeNames, &q.ExpressionAttributeValues,
+			func(names map[string]string, values map[string]Attribute) (string, map[string]string, map[string]Attribute, error) {
+				expr, names := projection.Build(names)
+				return expr, names, values, nil
+			})
+		if err != nil {
+			failQuery(q, err)
+			return
+		}
+		q.ProjectionExpression = aws.String(expr)
 	}
 }
 
@@ -29,6 +63,46 @@ func WithFilter(filterCon string) QueryOptions {
 	}
 }
 
+// WithKeyCondition replaces the key condition argument of Query with a key condition built by the expression package.
+// Placeholders are added next to the values passed to Query
+//
+//	table.Query(ctx, "", nil, &out, dynago.WithKeyCondition(
+//	  expression.Key("pk").Equal(pk).And(expression.Key("sk").BeginsWith("order#")),
+//	))
+func WithKeyCondition(key expression.KeyCondition) QueryOptions {
+	return func(q *dynamodb.QueryInput) {
+		if key.IsZero() {
+			return
+		}
+		expr, err := buildExpression(&q.ExpressionAttributeNames, &q.ExpressionAttributeValues,
+			func(names map[string]string, values map[string]Attribute) (string, map[string]string, map[string]Attribute, error) {
+				expr, names, values := key.Build(names, values)
+				return expr, names, values, nil
+			})
+		if err != nil {
+			failQuery(q, err)
+			return
+		}
+		q.KeyConditionExpression = aws.String(expr)
+	}
+}
+
+// WithFilterExpr only returns the items matching a condition built by the expression package.
+// A filter that can not be built, eg: In without values, makes the query return an ErrValidation
+func WithFilterExpr(filter expression.Condition) QueryOptions {
+	return func(q *dynamodb.QueryInput) {
+		if filter.IsZero() {
+			return
+		}
+		expr, err := buildExpression(&q.ExpressionAttributeNames, &q.ExpressionAttributeValues, filter.Build)
+		if err != nil {
+			failQuery(q, err)
+			return
+		}
+		q.FilterExpression = aws.String(expr)
+	}
+}
+
 func WithIndex(i string) QueryOptions {
 	index := aws.String(i)
 	return func(q *dynamodb.QueryInput) {
@@ -44,6 +118,8 @@ func SortByAsc(v bool) QueryOptions {
 	}
 }
 
+// WithLimit limits the number of items evaluated by Query, items removed by a filter expression are counted.
+// Use QueryPage to get a number of matching items instead
 func WithLimit(v int32) QueryOptions {
 	val := aws.Int32(v)
 	return func(q *dynamodb.QueryInput) {
@@ -57,11 +133,15 @@ func WithCursorKey(key map[string]Attribute) QueryOptions {
 	}
 }
 
-// TODO: improve with generics and avoid the `out` argument
+// Query performs a DynamoDB query with the key condition expression and unmarshals matching items into out.
+// Use Table.Query to get typed results without the `out` argument.
 func (t *Client) Query(
 	ctx context.Context,
 	condition string, values map[string]Attribute, out interface{}, opts ...QueryOptions,
 ) (cursor map[string]Attribute, err error) {
+	ctx, op := t.startOperation(ctx, "Query")
+	defer func() { op.end(err) }()
+
 	input := &dynamodb.QueryInput{
 		TableName:                 &t.TableName,
 		KeyConditionExpression:    aws.String(condition),
@@ -69,10 +149,13 @@ func (t *Client) Query(
 	}
 
 	// when optional function param is provided
-	if len(opts) > 0 {
-		for _, opt := range opts {
-			opt(input)
-		}
+	if err := applyQueryOptions(input, opts); err != nil {
+		return nil, err
+	}
+
+	op.setIndex(input.IndexName)
+	if err := t.validateCursor(input.IndexName, input.ExclusiveStartKey); err != nil {
+		return nil, err
 	}
 
 	// TODO: pre allocate 100 capacity? AttributeValue is an iterface; allocated capacity might be too small
@@ -86,8 +169,8 @@ func (t *Client) Query(
 	for {
 		resp, err := t.client.Query(ctx, input)
 		if err != nil {
-			log.Printf("dynamodb query %s failed; %s \n", condition, err)
-			return nil, err
+			t.log().ErrorContext(ctx, "query failed", slog.String("condition", aws.ToString(input.KeyConditionExpression)), slog.Any("error", err))
+			return nil, wrapError(err)
 		}
 
 		if resp.Items != nil {
@@ -112,10 +195,53 @@ func (t *Client) Query(
 		}
 	}
 
+	op.addItems(len(results))
 	err = attributevalue.UnmarshalListOfMaps(results, &out)
 	if err != nil {
-		log.Println("dynamodb unmarshal failed" + err.Error())
+		t.log().ErrorContext(ctx, "failed to unmarshal query results", slog.String("condition", aws.ToString(input.KeyConditionExpression)), slog.Any("error", err))
 		return nil, err
 	}
 	return input.ExclusiveStartKey, err
 }
+
+// QueryPage returns up to pageSize items matching the key condition and filter expression into out, reading as many
+// pages as needed. Unlike WithLimit, items removed by a filter expression do not count towards the page size and
+// WithLimit only sets the number of items evaluated per request.
+//
+// The returned cursor resumes right after the last item returned, so consecutive pages never skip or repeat items.
+// cursor is nil once all matching items were returned. out may be a *[]map[string]Attribute to read items as is
+//
+//	cursor, err := table.QueryPage(ctx, "pk = :pk", values, 20, &out, dynago.WithFilter("Age > :age"))
+func (t *Client) QueryPage(
+	ctx context.Context,
+	condition string, values map[string]Attribute, pageSize int32, out interface{}, opts ...QueryOptions,
+) (cursor map[string]Attribute, err error) {
+	ctx, op := t.startOperation(ctx, "QueryPage")
+	defer func() { op.end(err) }()
+
+	if pageSize < 1 {
+		return nil, fmt.Errorf("%w; page size must be at least 1", ErrValidation)
+	}
+	it := newIterator(ctx, t, func(item map[string]Attribute) (map[string]Attribute, error) {
+		return item, nil
+	}, condition, values, opts...)
+	it.limit, it.pageSize, it.op = pageSize, true, op
+	op.setIndex(it.input.IndexName)
+
+	results := make([]map[string]Attribute, 0, pageSize)
+	for item, err := range it.All() {
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+	if raw, ok := out.(*[]map[string]Attribute); ok {
+		*raw = results
+		return it.Cursor(), nil
+	}
+	if err := attributevalue.UnmarshalListOfMaps(results, out); err != nil {
+		t.log().ErrorContext(ctx, "failed to unmarshal query results", slog.String("condition", condition), slog.Any("error", err))
+		return nil, err
+	}
+	return it.Cursor(), nil
+}
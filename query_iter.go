@@ -0,0 +1,230 @@
+package dynago
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// Iterator lazily fetches query result pages, one page per DynamoDB request, instead of buffering every page in memory.
+// Iteration can be stopped at any point and continued later using Cursor.
+//
+//	it := table.QueryIter(ctx, "pk = :pk", values)
+//	for item, err := range it.All() {
+//	  if err != nil {
+//	    return err
+//	  }
+//	  if done(item) {
+//	    break
+//	  }
+//	}
+//	cursor := it.Cursor() // resumes right after the last item consumed
+type Iterator[T any] struct {
+	ctx       context.Context
+	client    *Client
+	input     *dynamodb.QueryInput
+	unmarshal func(map[string]Attribute) (T, error)
+
+	// limit is the maximum number of items yielded in total, 0 when unlimited
+	limit   int32
+	yielded int32
+	// pageSize keeps the Limit of the request, the number of items evaluated per request, instead of requesting
+	// only the number of items left to yield
+	pageSize bool
+
+	keyNames []string
+	cursor   map[string]Attribute
+	started  bool
+	done     bool
+	// err is the error of an option, yielded instead of reading the first page
+	err error
+
+	// op is the operation the pages are read for, each call of All is an operation of its own when nil
+	op *operation
+}
+
+func newIterator[T any](ctx context.Context, client *Client, unmarshal func(map[string]Attribute) (T, error),
+	condition string, values map[string]Attribute, opts ...QueryOptions,
+) *Iterator[T] {
+	input := &dynamodb.QueryInput{
+		TableName:                 &client.TableName,
+		KeyConditionExpression:    aws.String(condition),
+		ExpressionAttributeValues: values,
+	}
+	err := applyQueryOptions(input, opts)
+
+	it := &Iterator[T]{
+		ctx:       ctx,
+		client:    client,
+		input:     input,
+		unmarshal: unmarshal,
+		cursor:    input.ExclusiveStartKey,
+		err:       err,
+	}
+	if input.Limit != nil {
+		it.limit = *input.Limit
+	}
+	it.keyNames = client.keyNames(input.IndexName)
+	return it
+}
+
+// QueryIter performs a DynamoDB query that fetches result pages lazily while iterating.
+// WithLimit caps the total number of items yielded. See Iterator.
+//
+// Queries of a secondary index require the index to be registered in ClientOptions.Indexes, ErrValidation is yielded
+// otherwise
+func (t *Client) QueryIter(ctx context.Context, condition string, values map[string]Attribute, opts ...QueryOptions) *Iterator[map[string]Attribute] {
+	return newIterator(ctx, t, func(item map[string]Attribute) (map[string]Attribute, error) {
+		return item, nil
+	}, condition, values, opts...)
+}
+
+// All returns an iterator over the query results. Requests are made as items are consumed;
+// breaking out of the loop stops fetching. On failure the error is yielded once and iteration ends.
+//
+// Calling All again continues after the last item consumed. Each call is a QueryIter operation of its own, its
+// recorded duration excludes the time the loop body spends processing items, see ClientOptions.TracerProvider
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if it.done {
+			return
+		}
+		if it.op != nil {
+			it.read(it.ctx, it.op, yield)
+			return
+		}
+		ctx, op := it.client.startOperation(it.ctx, "QueryIter")
+		op.setIndex(it.input.IndexName)
+		op.end(it.read(ctx, op, yield))
+	}
+}
+
+// read yields the items of the remaining pages, returns the error yielded.
+// The operation is paused while the caller processes a yielded item
+func (it *Iterator[T]) read(ctx context.Context, op *operation, yield func(T, error) bool) error {
+	var zero T
+	emit := func(value T, err error) bool {
+		op.pause()
+		defer op.resume()
+		return yield(value, err)
+	}
+	if !it.started {
+		err := it.err
+		if err == nil {
+			err = it.validateIndex()
+		}
+		if err == nil {
+			err = it.client.validateCursor(it.input.IndexName, it.cursor)
+		}
+		if err != nil {
+			it.done = true
+			emit(zero, err)
+			return err
+		}
+	}
+	if it.started {
+		it.input.ExclusiveStartKey = it.cursor
+	}
+	it.started = true
+
+	for {
+		if it.limit > 0 && !it.pageSize {
+			it.input.Limit = aws.Int32(it.limit - it.yielded)
+		}
+		resp, err := it.client.client.Query(ctx, it.input)
+		if err != nil {
+			it.client.log().ErrorContext(ctx, "query failed", slog.String("condition", *it.input.KeyConditionExpression), slog.Any("error", err))
+			err = wrapError(err)
+			emit(zero, err)
+			return err
+		}
+		// LastEvaluatedKey holds every key attribute needed to resume, including keys of unregistered indexes
+		if len(resp.LastEvaluatedKey) > len(it.keyNames) {
+			it.keyNames = it.keyNames[:0]
+			for name := range resp.LastEvaluatedKey {
+				it.keyNames = append(it.keyNames, name)
+			}
+		}
+
+		for idx, item := range resp.Items {
+			value, err := it.unmarshal(item)
+			if err != nil {
+				it.client.log().ErrorContext(ctx, "failed to unmarshal query results", slog.String("condition", *it.input.KeyConditionExpression), slog.Any("error", err))
+				it.done = true
+				emit(zero, err)
+				return err
+			}
+
+			// cursor is updated before yielding so it is correct when the caller breaks out of the loop
+			last := idx == len(resp.Items)-1
+			switch {
+			case last && resp.LastEvaluatedKey == nil:
+				it.cursor = nil
+			case last:
+				it.cursor = resp.LastEvaluatedKey
+			default:
+				it.cursor = it.keysOf(item)
+			}
+			it.yielded++
+			limited := it.limit > 0 && it.yielded >= it.limit
+			if limited || (last && resp.LastEvaluatedKey == nil) {
+				it.done = true
+			}
+
+			op.addItems(1)
+			if !emit(value, nil) || limited {
+				return nil
+			}
+		}
+
+		// no items left on this page, items skipped by a filter expression do not need to be read again
+		it.cursor = resp.LastEvaluatedKey
+		if resp.LastEvaluatedKey == nil {
+			it.done = true
+			return nil
+		}
+		it.input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// Cursor returns the key to resume the query right after the last item consumed, for use with WithCursorKey.
+// Returns nil once all results were consumed
+func (it *Iterator[T]) Cursor() map[string]Attribute {
+	return it.cursor
+}
+
+// validateIndex rejects queries of unregistered indexes. Cursors of items in the middle of a page hold the keys of the
+// table and index, the index keys are only known for indexes registered in ClientOptions.Indexes
+func (it *Iterator[T]) validateIndex() error {
+	index := it.input.IndexName
+	if index == nil {
+		return nil
+	}
+	if _, ok := it.client.Indexes[*index]; !ok {
+		return fmt.Errorf("%w; index %s is not registered in ClientOptions.Indexes, cursors of its items can not be built", ErrValidation, *index)
+	}
+	return nil
+}
+
+func (it *Iterator[T]) keysOf(item map[string]Attribute) map[string]Attribute {
+	keys := make(map[string]Attribute, len(it.keyNames))
+	for _, name := range it.keyNames {
+		if v, ok := item[name]; ok {
+			keys[name] = v
+		}
+	}
+	return keys
+}
+
+// QueryIter performs a query that fetches result pages lazily while iterating, see Client.QueryIter
+func (t *Table[T]) QueryIter(ctx context.Context, condition string, values map[string]Attribute, opts ...QueryOptions) *Iterator[T] {
+	return newIterator(ctx, t.client, func(item map[string]Attribute) (out T, err error) {
+		err = attributevalue.UnmarshalMap(item, &out)
+		return out, err
+	}, condition, values, opts...)
+}
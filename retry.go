@@ -0,0 +1,81 @@
+package dynago
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy configures exponential backoff with jitter between retries of a request
+type RetryPolicy struct {
+	// Maximum number of attempts including the first request, must be at least 1
+	MaxAttempts int
+	// Delay before the first retry, doubled for each following retry
+	BaseDelay time.Duration
+	// Upper bound of the delay between two attempts, delays are not bounded when not set
+	MaxDelay time.Duration
+	// Fraction of each delay that is randomised, between 0 (no jitter) and 1 (full jitter)
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used when ClientOptions does not configure a retry policy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 8,
+	BaseDelay:   50 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      1,
+}
+
+// Backoff returns the delay to wait after the given failed attempt, starting at 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay > 0; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		// delays past the largest time.Duration stay at the largest time.Duration
+		if delay > math.MaxInt64/2 {
+			delay = math.MaxInt64
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	if jitter > 0 && delay > 0 {
+		spread := delay
+		if f := float64(delay) * jitter; f < float64(delay) {
+			spread = time.Duration(f)
+		}
+		spread = min(spread, math.MaxInt64-1)
+		delay = delay - spread + time.Duration(rand.Int64N(int64(spread)+1))
+	}
+	return delay
+}
+
+// wait sleeps for the backoff delay of attempt or until the context is done
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// orDefault returns DefaultRetryPolicy for an unset policy
+func (p RetryPolicy) orDefault() RetryPolicy {
+	if p.MaxAttempts < 1 {
+		return DefaultRetryPolicy
+	}
+	return p
+}
@@ -0,0 +1,65 @@
+package dynago_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oolio-group/dynago"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := dynago.RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for idx, want := range expected {
+		if got := policy.Backoff(idx + 1); got != want {
+			t.Errorf("expected backoff of attempt %d to be %s; got %s", idx+1, want, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for attempt := 1; attempt <= 10; attempt++ {
+		upper := expected[min(attempt, len(expected))-1]
+		got := policy.Backoff(attempt)
+		if got < upper/2 || got > upper {
+			t.Errorf("expected backoff of attempt %d to be within [%s, %s]; got %s", attempt, upper/2, upper, got)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffWithoutMaxDelay(t *testing.T) {
+	policy := dynago.RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond}
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		1600 * time.Millisecond,
+	}
+	for idx, want := range expected {
+		if got := policy.Backoff(idx + 1); got != want {
+			t.Errorf("expected backoff of attempt %d to be %s; got %s", idx+1, want, got)
+		}
+	}
+
+	// large attempt numbers do not overflow
+	for _, attempt := range []int{64, 100, 1 << 20} {
+		if got := policy.Backoff(attempt); got <= 0 {
+			t.Errorf("expected positive backoff of attempt %d; got %s", attempt, got)
+		}
+	}
+	policy.Jitter = 1
+	if got := policy.Backoff(1000); got < 0 {
+		t.Errorf("expected positive backoff with jitter; got %s", got)
+	}
+}
@@ -0,0 +1,277 @@
+package dynago
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/oolio-group/dynago/expression"
+)
+
+type ScanInput = dynamodb.ScanInput
+
+// Function Struct for providing option input params for Scan and ParallelScan
+type ScanOptions func(s *dynamodb.ScanInput) error
+
+// WithScanFields selects specific fields of the scanned items.
+// Field names are escaped using placeholders, nested attributes are separated by dots
+func WithScanFields(fields []string) ScanOptions {
+	projection := expression.Projection(fields...)
+	return func(s *dynamodb.ScanInput) error {
+		expr, err := buildExpression(&s.ExpressionAttributeNames, &s.ExpressionAttributeValues,
+			func(names map[string]string, values map[string]Attribute) (string, map[string]string, map[string]Attribute, error) {
+				expr, names := projection.Build(names)
+				return expr, names, values, nil
+			})
+		if err != nil {
+			return err
+		}
+		s.ProjectionExpression = aws.String(expr)
+		return nil
+	}
+}
+
+// WithScanFilter only returns items matching the filter expression.
+// If the expression contains template params eg: Age > :age, values should provide them.
+// values are merged with the values of other options
+func WithScanFilter(filter string, values map[string]Attribute) ScanOptions {
+	return func(s *dynamodb.ScanInput) error {
+		s.FilterExpression = aws.String(filter)
+		if len(values) == 0 {
+			return nil
+		}
+		merged := maps.Clone(s.ExpressionAttributeValues)
+		if merged == nil {
+			merged = map[string]Attribute{}
+		}
+		for k, v := range values {
+			if _, ok := merged[k]; ok {
+				return fmt.Errorf("filter value %s conflicts with the value of another option", k)
+			}
+			merged[k] = v
+		}
+		s.ExpressionAttributeValues = merged
+		return nil
+	}
+}
+
+// WithScanFilterExpr only returns the items matching a condition built by the expression package
+func WithScanFilterExpr(filter expression.Condition) ScanOptions {
+	return func(s *dynamodb.ScanInput) error {
+		if filter.IsZero() {
+			return nil
+		}
+		expr, err := buildExpression(&s.ExpressionAttributeNames, &s.ExpressionAttributeValues, filter.Build)
+		if err != nil {
+			return err
+		}
+		s.FilterExpression = aws.String(expr)
+		return nil
+	}
+}
+
+// WithScanIndex scans a secondary index instead of the table
+func WithScanIndex(i string) ScanOptions {
+	index := aws.String(i)
+	return func(s *dynamodb.ScanInput) error {
+		s.IndexName = index
+		return nil
+	}
+}
+
+// WithScanLimit limits the number of items evaluated.
+// Scan stops paginating once the limit is reached; ParallelScan applies the limit to each page of a segment
+func WithScanLimit(v int32) ScanOptions {
+	val := aws.Int32(v)
+	return func(s *dynamodb.ScanInput) error {
+		s.Limit = val
+		return nil
+	}
+}
+
+// WithScanCursorKey resumes the scan from the cursor returned by a previous Scan call
+func WithScanCursorKey(key map[string]Attribute) ScanOptions {
+	return func(s *dynamodb.ScanInput) error {
+		s.ExclusiveStartKey = key
+		return nil
+	}
+}
+
+// WithConsistentReadScan enables strongly consistent read for Scan operations
+func WithConsistentReadScan() ScanOptions {
+	return func(s *dynamodb.ScanInput) error {
+		s.ConsistentRead = aws.Bool(true)
+		return nil
+	}
+}
+
+// Scan reads every item in the table, or index when WithScanIndex is used, and unmarshals them into out.
+// Returns a cursor that can be passed to WithScanCursorKey to continue the scan; cursor is nil once the whole table was read
+func (t *Client) Scan(ctx context.Context, out interface{}, opts ...ScanOptions) (cursor map[string]Attribute, err error) {
+	ctx, op := t.startOperation(ctx, "Scan")
+	defer func() { op.end(err) }()
+
+	input := &dynamodb.ScanInput{
+		TableName: &t.TableName,
+	}
+	for _, opt := range opts {
+		if err := opt(input); err != nil {
+			return nil, err
+		}
+	}
+	op.setIndex(input.IndexName)
+	if err := t.validateCursor(input.IndexName, input.ExclusiveStartKey); err != nil {
+		return nil, err
+	}
+
+	results := []map[string]Attribute{}
+	var limit int32
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+
+	for {
+		resp, err := t.client.Scan(ctx, input)
+		if err != nil {
+			t.log().ErrorContext(ctx, "scan failed", slog.Any("error", err))
+			return nil, wrapError(err)
+		}
+		results = append(results, resp.Items...)
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+
+		if input.Limit != nil {
+			if len(results) >= int(limit) {
+				break
+			}
+			input.Limit = aws.Int32(limit - int32(len(results)))
+		}
+		if resp.LastEvaluatedKey == nil {
+			break
+		}
+	}
+
+	op.addItems(len(results))
+	err = attributevalue.UnmarshalListOfMaps(results, &out)
+	if err != nil {
+		t.log().ErrorContext(ctx, "failed to unmarshal scan results", slog.Any("error", err))
+		return nil, err
+	}
+	return input.ExclusiveStartKey, nil
+}
+
+// ScanHandler receives each page of items read by a ParallelScan segment.
+// Handlers of different segments are called concurrently
+type ScanHandler func(ctx context.Context, segment int32, items []map[string]Attribute) error
+
+// ParallelScan splits the table into segments that are scanned concurrently, one worker per segment.
+// Each page read is passed to handler; returning an error from handler stops all segments.
+//
+// One cursor per segment is returned. A nil cursor marks a finished segment and an empty cursor a segment that has not
+// read any page yet. When the scan is interrupted by an error or context cancellation, pass the returned cursors back to
+// ParallelScan with the same number of segments to resume. A page that failed in handler is delivered again on resume.
+// Pass nil cursors to start a new scan
+func (t *Client) ParallelScan(
+	ctx context.Context,
+	segments int32, cursors []map[string]Attribute, handler ScanHandler, opts ...ScanOptions,
+) (_ []map[string]Attribute, err error) {
+	ctx, op := t.startOperation(ctx, "ParallelScan")
+	defer func() { op.end(err) }()
+
+	if segments < 1 {
+		return nil, fmt.Errorf("parallel scan requires at least 1 segment; got %d", segments)
+	}
+	if cursors != nil && len(cursors) != int(segments) {
+		return nil, fmt.Errorf("parallel scan expected %d cursors; got %d", segments, len(cursors))
+	}
+
+	base := dynamodb.ScanInput{
+		TableName:     &t.TableName,
+		TotalSegments: aws.Int32(segments),
+	}
+	for _, opt := range opts {
+		if err := opt(&base); err != nil {
+			return nil, err
+		}
+	}
+	op.setIndex(base.IndexName)
+	for _, cursor := range cursors {
+		// empty cursors mark segments that have not been read yet
+		if len(cursor) == 0 {
+			continue
+		}
+		if err := t.validateCursor(base.IndexName, cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		next     = make([]map[string]Attribute, segments)
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for segment := int32(0); segment < segments; segment++ {
+		start := map[string]Attribute{}
+		if cursors != nil {
+			start = cursors[segment]
+		}
+		// nil cursor of a resumed scan means the segment was already read completely
+		if start == nil {
+			continue
+		}
+		next[segment] = start
+
+		wg.Add(1)
+		go func(segment int32) {
+			defer wg.Done()
+			// every segment scans with its own copy of the input built from the options
+			input := base
+			input.Segment = aws.Int32(segment)
+			if len(next[segment]) > 0 {
+				input.ExclusiveStartKey = next[segment]
+			}
+
+			for {
+				if err := ctx.Err(); err != nil {
+					fail(err)
+					return
+				}
+				resp, err := t.client.Scan(ctx, &input)
+				if err != nil {
+					t.log().ErrorContext(ctx, "scan failed", slog.Int("segment", int(segment)), slog.Any("error", err))
+					fail(wrapError(err))
+					return
+				}
+				op.addItems(len(resp.Items))
+				if err := handler(ctx, segment, resp.Items); err != nil {
+					fail(err)
+					return
+				}
+
+				next[segment] = resp.LastEvaluatedKey
+				if resp.LastEvaluatedKey == nil {
+					return
+				}
+				input.ExclusiveStartKey = resp.LastEvaluatedKey
+			}
+		}(segment)
+	}
+	wg.Wait()
+
+	return next, firstErr
+}
@@ -0,0 +1,164 @@
+package dynago
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// MaxItemSize is the largest item DynamoDB stores, including attribute names
+	MaxItemSize = 400 * 1024
+	// MaxTransactionItems is the largest number of actions in a transaction
+	MaxTransactionItems = 100
+	// MaxTransactionSize is the largest aggregate size of the items in a transaction
+	MaxTransactionSize = 4 * 1024 * 1024
+)
+
+// ItemSizeError is returned before a request is sent when an item is larger than MaxItemSize.
+// It matches ErrValidation
+type ItemSizeError struct {
+	// Position of the item in the request
+	Index int
+	Size  int
+}
+
+func (e *ItemSizeError) Error() string {
+	return fmt.Sprintf("item %d is %d bytes; exceeds the maximum item size of %d bytes", e.Index, e.Size, MaxItemSize)
+}
+
+func (e *ItemSizeError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// TransactionSizeError is returned before a transaction is sent when it has more than MaxTransactionItems actions or
+// its items are larger than MaxTransactionSize in total. It matches ErrValidation
+type TransactionSizeError struct {
+	Items int
+	Size  int
+}
+
+func (e *TransactionSizeError) Error() string {
+	if e.Items > MaxTransactionItems {
+		return fmt.Sprintf("transaction has %d items; exceeds the maximum of %d items", e.Items, MaxTransactionItems)
+	}
+	return fmt.Sprintf("transaction is %d bytes; exceeds the maximum transaction size of %d bytes", e.Size, MaxTransactionSize)
+}
+
+func (e *TransactionSizeError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ItemSize returns the size of an item as DynamoDB computes it, the sum of the lengths of attribute names and the
+// sizes of their values. See https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/CapacityUnitCalculations.html
+func ItemSize(item map[string]Attribute) int {
+	size := 0
+	for name, v := range item {
+		size += len(name) + AttributeSize(v)
+	}
+	return size
+}
+
+// AttributeSize returns the size of an attribute value without its name:
+//   - strings and binaries are their length in bytes
+//   - numbers are 1 byte plus 1 byte per two significant digits
+//   - booleans and nulls are 1 byte
+//   - sets are the sum of the sizes of their elements
+//   - lists and maps are 3 bytes plus the size of every element and 1 byte per element
+func AttributeSize(v Attribute) int {
+	switch v := v.(type) {
+	case *types.AttributeValueMemberS:
+		return len(v.Value)
+	case *types.AttributeValueMemberN:
+		return numberSize(v.Value)
+	case *types.AttributeValueMemberB:
+		return len(v.Value)
+	case *types.AttributeValueMemberBOOL, *types.AttributeValueMemberNULL:
+		return 1
+	case *types.AttributeValueMemberSS:
+		size := 0
+		for _, s := range v.Value {
+			size += len(s)
+		}
+		return size
+	case *types.AttributeValueMemberNS:
+		size := 0
+		for _, n := range v.Value {
+			size += numberSize(n)
+		}
+		return size
+	case *types.AttributeValueMemberBS:
+		size := 0
+		for _, b := range v.Value {
+			size += len(b)
+		}
+		return size
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, e := range v.Value {
+			size += AttributeSize(e) + 1
+		}
+		return size
+	case *types.AttributeValueMemberM:
+		size := 3
+		for name, e := range v.Value {
+			size += len(name) + AttributeSize(e) + 1
+		}
+		return size
+	}
+	return 0
+}
+
+// numberSize counts the significant digits of a number, leading and trailing zeros are not stored
+func numberSize(n string) int {
+	if i := strings.IndexAny(n, "eE"); i >= 0 {
+		n = n[:i]
+	}
+	n = strings.TrimLeft(n, "+-")
+	n = strings.Replace(n, ".", "", 1)
+	n = strings.Trim(n, "0")
+	return (len(n)+1)/2 + 1
+}
+
+// checkItemSize returns an ItemSizeError when item is larger than MaxItemSize
+func checkItemSize(index int, item map[string]Attribute) error {
+	if size := ItemSize(item); size > MaxItemSize {
+		return &ItemSizeError{Index: index, Size: size}
+	}
+	return nil
+}
+
+// checkTransaction validates the number of actions and the size of the items of a transaction, items without a put,
+// update, delete or condition check are rejected. Only items written by puts are known before the request, so the aggregate size of updates, deletes and condition
+// checks is counted as the size of their keys
+func checkTransaction(items []types.TransactWriteItem) error {
+	if len(items) > MaxTransactionItems {
+		return &TransactionSizeError{Items: len(items)}
+	}
+	total := 0
+	for idx, item := range items {
+		var av map[string]Attribute
+		switch {
+		case item.Put != nil:
+			av = item.Put.Item
+		case item.Update != nil:
+			av = item.Update.Key
+		case item.Delete != nil:
+			av = item.Delete.Key
+		case item.ConditionCheck != nil:
+			av = item.ConditionCheck.Key
+		default:
+			return fmt.Errorf("%w; transaction item %d has no put, update, delete or condition check", ErrValidation, idx)
+		}
+		size := ItemSize(av)
+		if size > MaxItemSize {
+			return &ItemSizeError{Index: idx, Size: size}
+		}
+		total += size
+	}
+	if total > MaxTransactionSize {
+		return &TransactionSizeError{Items: len(items), Size: total}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package dynago_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+func TestItemSize(t *testing.T) {
+	cases := []struct {
+		item     map[string]dynago.Attribute
+		expected int
+	}{
+		{item: map[string]dynago.Attribute{"name": dynago.StringValue("hello")}, expected: 4 + 5},
+		{item: map[string]dynago.Attribute{"n": dynago.NumberValue(0)}, expected: 1 + 1},
+		{item: map[string]dynago.Attribute{"n": dynago.NumberValue(12345)}, expected: 1 + 4},
+		{item: map[string]dynago.Attribute{"n": &types.AttributeValueMemberN{Value: "-0012.3400"}}, expected: 1 + 3},
+		{item: map[string]dynago.Attribute{"n": &types.AttributeValueMemberN{Value: "1.5E+10"}}, expected: 1 + 2},
+		{item: map[string]dynago.Attribute{"b": &types.AttributeValueMemberB{Value: []byte{1, 2, 3}}}, expected: 1 + 3},
+		{item: map[string]dynago.Attribute{"ok": dynago.BoolValue(true), "x": &types.AttributeValueMemberNULL{Value: true}}, expected: 2 + 1 + 1 + 1},
+		{item: map[string]dynago.Attribute{"ss": dynago.StringSetValue("ab", "cde")}, expected: 2 + 5},
+		{item: map[string]dynago.Attribute{"ns": dynago.NumberSetValue(1, 100)}, expected: 2 + 2 + 2},
+		{
+			item: map[string]dynago.Attribute{"l": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				dynago.StringValue("ab"), dynago.BoolValue(false),
+			}}},
+			expected: 1 + 3 + (2 + 1) + (1 + 1),
+		},
+		{
+			item: map[string]dynago.Attribute{"m": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"k": dynago.StringValue("v"),
+			}}},
+			expected: 1 + 3 + (1 + 1 + 1),
+		},
+		{item: map[string]dynago.Attribute{"l": &types.AttributeValueMemberL{}}, expected: 1 + 3},
+	}
+
+	for idx, tc := range cases {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			if got := dynago.ItemSize(tc.item); got != tc.expected {
+				t.Errorf("expected size %d; got %d", tc.expected, got)
+			}
+		})
+	}
+}
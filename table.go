@@ -0,0 +1,107 @@
+package dynago
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+// Table is a typed view of a Client. Items read through a Table are unmarshalled into T,
+// so using the wrong destination type is a compile error instead of a runtime unmarshal failure.
+// Keys are still built by Client.NewKeys using the key names registered in ClientOptions.
+//
+//	users := dynago.NewTable[User](client)
+//	user, found, err := users.Get(ctx, dynago.StringValue("user#1"), dynago.StringValue("user#1"))
+type Table[T any] struct {
+	client *Client
+}
+
+// NewTable creates a typed Table for items of type T stored in the table of the given client
+func NewTable[T any](client *Client) *Table[T] {
+	return &Table[T]{client: client}
+}
+
+// Client returns the underlying untyped client
+func (t *Table[T]) Client() *Client {
+	return t.client
+}
+
+// Get fetches a single item by partition key and sort key.
+// found is false when the item does not exist, in which case item is the zero value of T.
+func (t *Table[T]) Get(ctx context.Context, pk, sk Attribute, opts ...GetItemOptions) (item T, found bool, err error) {
+	err, found = t.client.GetItem(ctx, pk, sk, &item, opts...)
+	return item, found, err
+}
+
+// Put creates or replaces item at the given partition key and sort key
+func (t *Table[T]) Put(ctx context.Context, pk, sk Attribute, item T, opts ...PutOption) error {
+	return t.client.PutItem(ctx, pk, sk, item, opts...)
+}
+
+// Query performs a DynamoDB query with the key condition expression and values, see Client.Query.
+// The returned cursor can be passed to WithCursorKey to fetch the next page; it is nil on the last page.
+func (t *Table[T]) Query(ctx context.Context, condition string, values map[string]Attribute, opts ...QueryOptions) (items []T, cursor map[string]Attribute, err error) {
+	cursor, err = t.client.Query(ctx, condition, values, &items, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, cursor, nil
+}
+
+// QueryPage returns up to pageSize items matching the key condition and filter expression, see Client.QueryPage.
+// The returned cursor resumes right after the last item returned; it is nil once all matching items were returned
+func (t *Table[T]) QueryPage(ctx context.Context, condition string, values map[string]Attribute, pageSize int32, opts ...QueryOptions) (items []T, cursor map[string]Attribute, err error) {
+	cursor, err = t.client.QueryPage(ctx, condition, values, pageSize, &items, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, cursor, nil
+}
+
+// BatchGet fetches all items matching the given keys. Keys can be built using Client.NewKeys.
+// Items that do not exist are omitted; the order of returned items is not guaranteed to match keys.
+func (t *Table[T]) BatchGet(ctx context.Context, keys []AttributeRecord) (items []T, err error) {
+	err = t.client.BatchGetItems(ctx, keys, &items)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// TransactGet reads the items matching the given keys as a single serializable snapshot, see Client.TransactGetItems.
+// items and found follow the order of keys; found is false for items that do not exist, which are the zero value of T
+func (t *Table[T]) TransactGet(ctx context.Context, keys []AttributeRecord) (items []T, found []bool, err error) {
+	items = make([]T, len(keys))
+	inputs := make([]*TransactGetItemsInput, len(keys))
+	for idx, key := range keys {
+		inputs[idx] = &TransactGetItemsInput{Key: key, Out: &items[idx]}
+	}
+	found, err = t.client.TransactGetItems(ctx, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, found, nil
+}
+
+// Scan reads every item of the table, see Client.Scan
+func (t *Table[T]) Scan(ctx context.Context, opts ...ScanOptions) (items []T, cursor map[string]Attribute, err error) {
+	cursor, err = t.client.Scan(ctx, &items, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, cursor, nil
+}
+
+// ParallelScan scans the table with concurrent segment workers and passes each page to handler as []T, see Client.ParallelScan
+func (t *Table[T]) ParallelScan(
+	ctx context.Context,
+	segments int32, cursors []map[string]Attribute, handler func(ctx context.Context, segment int32, items []T) error, opts ...ScanOptions,
+) ([]map[string]Attribute, error) {
+	return t.client.ParallelScan(ctx, segments, cursors, func(ctx context.Context, segment int32, page []map[string]Attribute) error {
+		var items []T
+		if err := attributevalue.UnmarshalListOfMaps(page, &items); err != nil {
+			return err
+		}
+		return handler(ctx, segment, items)
+	}, opts...)
+}
@@ -0,0 +1,408 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName is the name of the tracer and meter of the client
+const instrumentationName = "github.com/oolio-group/dynago"
+
+// Attributes of operation spans and metrics. Names follow the OpenTelemetry semantic conventions for DynamoDB,
+// dynago.* attributes describe how an operation was split into requests
+const (
+	AttrOperation        = attribute.Key("db.operation.name")
+	AttrTableNames       = attribute.Key("aws.dynamodb.table_names")
+	AttrIndexName        = attribute.Key("aws.dynamodb.index_name")
+	AttrErrorType        = attribute.Key("error.type")
+	AttrItems            = attribute.Key("dynago.items")
+	AttrPages            = attribute.Key("dynago.pages")
+	AttrChunks           = attribute.Key("dynago.chunks")
+	AttrAttempts         = attribute.Key("dynago.attempts")
+	AttrConsumedCapacity = attribute.Key("dynago.consumed_capacity")
+)
+
+// Names of the metrics recorded for every operation
+const (
+	MetricOperationDuration = "dynago.operation.duration"
+	MetricOperationErrors   = "dynago.operation.errors"
+)
+
+var attrSystem = attribute.String("db.system.name", "aws.dynamodb")
+
+// telemetry holds the tracer and instruments of a client
+type telemetry struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+var noTelemetry = newTelemetry(ClientOptions{})
+
+func newTelemetry(opt ClientOptions) *telemetry {
+	tp, mp := opt.TracerProvider, opt.MeterProvider
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+	tel := &telemetry{tracer: tp.Tracer(instrumentationName)}
+	// instruments are noop when the meter provider fails to create them
+	tel.duration, _ = meter.Float64Histogram(MetricOperationDuration,
+		metric.WithDescription("Duration of dynago operations, including retries and every page read, excluding the time QueryIter loops spend processing items"),
+		metric.WithUnit("s"))
+	tel.errors, _ = meter.Int64Counter(MetricOperationErrors,
+		metric.WithDescription("Number of dynago operations that returned an error"),
+		metric.WithUnit("{operation}"))
+	if tel.duration == nil || tel.errors == nil {
+		noop := metricnoop.Meter{}
+		tel.duration, _ = noop.Float64Histogram(MetricOperationDuration)
+		tel.errors, _ = noop.Int64Counter(MetricOperationErrors)
+	}
+	return tel
+}
+
+// tel returns the telemetry of the client, a zero Client records nothing
+func (t *Client) tel() *telemetry {
+	if t.telemetry == nil {
+		return noTelemetry
+	}
+	return t.telemetry
+}
+
+// operation is a dynago operation in progress, it can span several DynamoDB requests.
+// Methods are safe for concurrent use and do nothing on a nil operation
+type operation struct {
+	tel  *telemetry
+	span trace.Span
+	name string
+
+	mu sync.Mutex
+	// start is when the operation started or was last resumed, elapsed the time it ran before being paused
+	start    time.Time
+	elapsed  time.Duration
+	paused   bool
+	tables   []string
+	index    string
+	items    int
+	pages    int
+	chunks   int
+	attempts int
+	capacity float64
+}
+
+type operationKey struct{}
+
+// startOperation starts the span of an operation of the client. The operation is stored in the returned context
+// so requests sent with it add their consumed capacity and pages to the operation
+func (t *Client) startOperation(ctx context.Context, name string) (context.Context, *operation) {
+	op := &operation{tel: t.tel(), name: name, start: time.Now(), tables: []string{t.TableName}}
+	ctx, op.span = op.tel.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrSystem, AttrOperation.String(name)))
+	return context.WithValue(ctx, operationKey{}, op), op
+}
+
+// operationFrom returns the innermost operation started with ctx, nil when there is none
+func operationFrom(ctx context.Context) *operation {
+	op, _ := ctx.Value(operationKey{}).(*operation)
+	return op
+}
+
+func (o *operation) setIndex(name *string) {
+	if o == nil || name == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.index = *name
+}
+
+func (o *operation) addItems(n int) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.items += n
+}
+
+func (o *operation) addPage() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pages++
+}
+
+func (o *operation) addChunk() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.chunks++
+}
+
+func (o *operation) setAttempts(n int) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.attempts = n
+}
+
+// addCapacity adds the capacity consumed by a request, tables of other clients are added to the table names
+func (o *operation) addCapacity(capacity ...types.ConsumedCapacity) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, c := range capacity {
+		if c.CapacityUnits != nil {
+			o.capacity += *c.CapacityUnits
+		}
+		if c.TableName != nil && !slices.Contains(o.tables, *c.TableName) {
+			o.tables = append(o.tables, *c.TableName)
+		}
+	}
+}
+
+// pause stops counting the duration of the operation while the caller processes its results, see resume
+func (o *operation) pause() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.paused {
+		o.elapsed += time.Since(o.start)
+		o.paused = true
+	}
+}
+
+// resume counts the duration of a paused operation again
+func (o *operation) resume() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.paused {
+		o.start = time.Now()
+		o.paused = false
+	}
+}
+
+// end ends the span of the operation and records its duration, not counting the time it was paused.
+// err is the error returned by the operation
+func (o *operation) end(err error) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	duration := o.elapsed
+	if !o.paused {
+		duration += time.Since(o.start)
+	}
+	attrs := []attribute.KeyValue{attrSystem, AttrOperation.String(o.name), AttrTableNames.StringSlice(o.tables)}
+	if o.index != "" {
+		attrs = append(attrs, AttrIndexName.String(o.index))
+	}
+	if err != nil {
+		attrs = append(attrs, AttrErrorType.String(errorType(err)))
+	}
+	ctx := context.Background()
+	o.tel.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+	if err != nil {
+		o.tel.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+
+	if !o.span.IsRecording() {
+		o.span.End()
+		return
+	}
+	attrs = append(attrs, AttrItems.Int(o.items), AttrConsumedCapacity.Float64(o.capacity))
+	if o.pages > 0 {
+		attrs = append(attrs, AttrPages.Int(o.pages))
+	}
+	if o.chunks > 0 {
+		attrs = append(attrs, AttrChunks.Int(o.chunks))
+	}
+	if o.attempts > 0 {
+		attrs = append(attrs, AttrAttempts.Int(o.attempts))
+	}
+	o.span.SetAttributes(attrs...)
+	if err != nil {
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+}
+
+// errorType returns the error.type attribute of an error, the kind of the error rather than its message
+func errorType(err error) string {
+	var (
+		txErr    *TransactionCanceledError
+		batchErr *BatchWriteError
+		sizeErr  *ItemSizeError
+	)
+	switch {
+	case errors.As(err, &txErr):
+		return "transaction_canceled"
+	case errors.As(err, &batchErr) && batchErr.Err == nil:
+		return "unprocessed_items"
+	case errors.As(err, &sizeErr):
+		return "item_size"
+	case errors.Is(err, ErrConditionFailed):
+		return "condition_failed"
+	case errors.Is(err, ErrThrottled):
+		return "throttled"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrValidation):
+		return "validation"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	}
+	return "_OTHER"
+}
+
+// tracedAPI asks DynamoDB for the capacity consumed by each request and adds it, and the number of pages read,
+// to the operation of the request context. It is used when ClientOptions.TracerProvider is set
+type tracedAPI struct {
+	DynamoDBAPI
+}
+
+func (a tracedAPI) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.GetItem(ctx, params, optFns...)
+	if err == nil && out.ConsumedCapacity != nil {
+		operationFrom(ctx).addCapacity(*out.ConsumedCapacity)
+	}
+	return out, err
+}
+
+func (a tracedAPI) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.PutItem(ctx, params, optFns...)
+	if err == nil && out.ConsumedCapacity != nil {
+		operationFrom(ctx).addCapacity(*out.ConsumedCapacity)
+	}
+	return out, err
+}
+
+func (a tracedAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.UpdateItem(ctx, params, optFns...)
+	if err == nil && out.ConsumedCapacity != nil {
+		operationFrom(ctx).addCapacity(*out.ConsumedCapacity)
+	}
+	return out, err
+}
+
+func (a tracedAPI) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.DeleteItem(ctx, params, optFns...)
+	if err == nil && out.ConsumedCapacity != nil {
+		operationFrom(ctx).addCapacity(*out.ConsumedCapacity)
+	}
+	return out, err
+}
+
+func (a tracedAPI) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.Query(ctx, params, optFns...)
+	if err == nil {
+		op := operationFrom(ctx)
+		op.addPage()
+		if out.ConsumedCapacity != nil {
+			op.addCapacity(*out.ConsumedCapacity)
+		}
+	}
+	return out, err
+}
+
+func (a tracedAPI) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.Scan(ctx, params, optFns...)
+	if err == nil {
+		op := operationFrom(ctx)
+		op.addPage()
+		if out.ConsumedCapacity != nil {
+			op.addCapacity(*out.ConsumedCapacity)
+		}
+	}
+	return out, err
+}
+
+func (a tracedAPI) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.BatchGetItem(ctx, params, optFns...)
+	if err == nil {
+		operationFrom(ctx).addCapacity(out.ConsumedCapacity...)
+	}
+	return out, err
+}
+
+func (a tracedAPI) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.BatchWriteItem(ctx, params, optFns...)
+	if err == nil {
+		operationFrom(ctx).addCapacity(out.ConsumedCapacity...)
+	}
+	return out, err
+}
+
+func (a tracedAPI) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.TransactWriteItems(ctx, params, optFns...)
+	if err == nil {
+		operationFrom(ctx).addCapacity(out.ConsumedCapacity...)
+	}
+	return out, err
+}
+
+func (a tracedAPI) TransactGetItems(ctx context.Context, params *dynamodb.TransactGetItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactGetItemsOutput, error) {
+	returnCapacity(&params.ReturnConsumedCapacity)
+	out, err := a.DynamoDBAPI.TransactGetItems(ctx, params, optFns...)
+	if err == nil {
+		operationFrom(ctx).addCapacity(out.ConsumedCapacity...)
+	}
+	return out, err
+}
+
+// returnCapacity asks for the total consumed capacity unless the request already asks for it
+func returnCapacity(v *types.ReturnConsumedCapacity) {
+	if *v == "" || *v == types.ReturnConsumedCapacityNone {
+		*v = types.ReturnConsumedCapacityTotal
+	}
+}
+
+// rawAPI returns the DynamoDB API the client was created with, without instrumentation
+func rawAPI(api DynamoDBAPI) DynamoDBAPI {
+	if traced, ok := api.(tracedAPI); ok {
+		return traced.DynamoDBAPI
+	}
+	return api
+}
@@ -41,27 +41,56 @@ func (db *TestDatabase) ready() error {
 	return nil
 }
 
-func (db TestDatabase) CreateTable(ctx context.Context, tableName, pk, sk string) error {
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithCredentialsProvider(db.Credentials()),
-	)
-	if err != nil {
-		return err
+// TableOption customises the table created by CreateTable
+type TableOption func(*dynamodb.CreateTableInput)
+
+// WithKeyTypes sets the attribute types of the partition and sort keys, empty types keep the default string type.
+// The sort key type is ignored for tables without a sort key
+func WithKeyTypes(pk, sk types.ScalarAttributeType) TableOption {
+	return func(input *dynamodb.CreateTableInput) {
+		if pk != "" {
+			input.AttributeDefinitions[0].AttributeType = pk
+		}
+		if sk != "" && len(input.AttributeDefinitions) > 1 {
+			input.AttributeDefinitions[1].AttributeType = sk
+		}
 	}
-	client := dynamodb.NewFromConfig(cfg, dynamodb.WithEndpointResolverV2(db))
-	_, err = client.CreateTable(ctx, &dynamodb.CreateTableInput{
-		AttributeDefinitions: []types.AttributeDefinition{{
-			AttributeName: &pk,
-			AttributeType: types.ScalarAttributeTypeS,
-		}, {AttributeName: &sk, AttributeType: types.ScalarAttributeTypeS}},
+}
+
+// CreateTable creates a table with string partition and sort keys, use WithKeyTypes for numeric or binary keys.
+// A table without sort key is created when sk is empty
+func (db TestDatabase) CreateTable(ctx context.Context, tableName, pk, sk string, opts ...TableOption) error {
+	input := &dynamodb.CreateTableInput{
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: &pk, AttributeType: types.ScalarAttributeTypeS},
+		},
 		KeySchema: []types.KeySchemaElement{
 			{AttributeName: &pk, KeyType: types.KeyTypeHash},
-			{AttributeName: &sk, KeyType: types.KeyTypeRange},
 		},
 		TableName:   &tableName,
 		BillingMode: types.BillingModePayPerRequest,
 		TableClass:  types.TableClassStandard,
-	})
+	}
+	if sk != "" {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{AttributeName: &sk, AttributeType: types.ScalarAttributeTypeS})
+		input.KeySchema = append(input.KeySchema, types.KeySchemaElement{AttributeName: &sk, KeyType: types.KeyTypeRange})
+	}
+	for _, opt := range opts {
+		opt(input)
+	}
+	return db.CreateTableFromInput(ctx, input)
+}
+
+// CreateTableFromInput creates a table from a DynamoDB CreateTable request, eg: a table with secondary indexes
+func (db TestDatabase) CreateTableFromInput(ctx context.Context, input *dynamodb.CreateTableInput) error {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithCredentialsProvider(db.Credentials()),
+	)
+	if err != nil {
+		return err
+	}
+	client := dynamodb.NewFromConfig(cfg, dynamodb.WithEndpointResolverV2(db))
+	_, err = client.CreateTable(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to create table %w", err)
 	}
@@ -0,0 +1,50 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// readUnits returns the read capacity consumed by reading size bytes, rounded up to 4KB.
+// Eventually consistent reads consume half the capacity
+func readUnits(size int, consistent *bool) float64 {
+	units := float64(max(1, (size+4095)/4096))
+	if consistent == nil || !*consistent {
+		units /= 2
+	}
+	return units
+}
+
+// writeUnits returns the write capacity consumed by writing size bytes, rounded up to 1KB
+func writeUnits(size int) float64 {
+	return float64(max(1, (size+1023)/1024))
+}
+
+func returnCapacity(rc types.ReturnConsumedCapacity) bool {
+	return rc == types.ReturnConsumedCapacityTotal || rc == types.ReturnConsumedCapacityIndexes
+}
+
+func readCapacity(rc types.ReturnConsumedCapacity, tableName string, size int, consistent *bool) *types.ConsumedCapacity {
+	if !returnCapacity(rc) {
+		return nil
+	}
+	return &types.ConsumedCapacity{TableName: aws.String(tableName), CapacityUnits: aws.Float64(readUnits(size, consistent))}
+}
+
+func writeCapacity(rc types.ReturnConsumedCapacity, tableName string, size int) *types.ConsumedCapacity {
+	if !returnCapacity(rc) {
+		return nil
+	}
+	return &types.ConsumedCapacity{TableName: aws.String(tableName), CapacityUnits: aws.Float64(writeUnits(size))}
+}
+
+func tableCapacity(rc types.ReturnConsumedCapacity, units map[string]float64) []types.ConsumedCapacity {
+	if !returnCapacity(rc) {
+		return nil
+	}
+	var out []types.ConsumedCapacity
+	for _, name := range sortedKeys(units) {
+		out = append(out, types.ConsumedCapacity{TableName: aws.String(name), CapacityUnits: aws.Float64(units[name])})
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+)
+
+// operationError wraps err the way the AWS SDK reports failed operations
+// so callers can use errors.As with the SDK error types
+func operationError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &smithy.OperationError{ServiceID: "DynamoDB", OperationName: operation, Err: err}
+}
+
+func validationError(message string) error {
+	return &smithy.GenericAPIError{Code: "ValidationException", Message: message, Fault: smithy.FaultClient}
+}
+
+func resourceNotFound(name string) error {
+	return &types.ResourceNotFoundException{Message: aws.String("Requested resource not found: Table: " + name + " not found")}
+}
+
+func conditionFailed(old item, returnOld bool) error {
+	err := &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	if returnOld && old != nil {
+		err.Item = copyItem(old)
+	}
+	return err
+}
@@ -0,0 +1,274 @@
+package memdb
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// getPath returns the value at the document path p
+func getPath(in item, p path) (types.AttributeValue, bool) {
+	var current types.AttributeValue = &types.AttributeValueMemberM{Value: in}
+	for _, e := range p {
+		switch v := current.(type) {
+		case *types.AttributeValueMemberM:
+			if e.list {
+				return nil, false
+			}
+			next, ok := v.Value[e.name]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case *types.AttributeValueMemberL:
+			if !e.list || e.index >= len(v.Value) {
+				return nil, false
+			}
+			current = v.Value[e.index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setPath stores value at the document path p. The parent of the path must exist,
+// list indexes past the end of a list append to the list
+func setPath(in item, p path, value types.AttributeValue) error {
+	parent, ok := getPath(in, p[:len(p)-1])
+	if !ok {
+		return fmt.Errorf("The document path provided in the update expression is invalid for update")
+	}
+	last := p[len(p)-1]
+	switch v := parent.(type) {
+	case *types.AttributeValueMemberM:
+		if !last.list {
+			v.Value[last.name] = value
+			return nil
+		}
+	case *types.AttributeValueMemberL:
+		if last.list {
+			if last.index >= len(v.Value) {
+				v.Value = append(v.Value, value)
+			} else {
+				v.Value[last.index] = value
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("The document path provided in the update expression is invalid for update")
+}
+
+// removePath deletes the value at the document path p, missing paths are ignored
+func removePath(in item, p path) {
+	parent, ok := getPath(in, p[:len(p)-1])
+	if !ok {
+		return
+	}
+	last := p[len(p)-1]
+	switch v := parent.(type) {
+	case *types.AttributeValueMemberM:
+		if !last.list {
+			delete(v.Value, last.name)
+		}
+	case *types.AttributeValueMemberL:
+		if last.list && last.index < len(v.Value) {
+			v.Value = append(v.Value[:last.index], v.Value[last.index+1:]...)
+		}
+	}
+}
+
+// applyUpdate returns a copy of in with the update applied. Values are evaluated against the original item
+func applyUpdate(in item, u *updateExpression) (item, error) {
+	out := copyItem(in)
+	for _, a := range u.set {
+		value, err := a.value.value(in)
+		if err != nil {
+			return nil, err
+		}
+		if err := setPath(out, a.path, copyValue(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	// list elements are removed from the highest index so earlier removals do not shift later ones
+	removes := append([]path(nil), u.remove...)
+	sort.SliceStable(removes, func(i, j int) bool {
+		a, b := removes[i], removes[j]
+		if pa, pb := a[:len(a)-1].String(), b[:len(b)-1].String(); pa != pb {
+			return pa < pb
+		}
+		return a[len(a)-1].index > b[len(b)-1].index
+	})
+	for _, p := range removes {
+		removePath(out, p)
+	}
+
+	for _, a := range u.add {
+		current, exists := getPath(out, a.path)
+		if !exists {
+			switch a.value.(type) {
+			case *types.AttributeValueMemberN, *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+			default:
+				return nil, fmt.Errorf("Invalid UpdateExpression: Incorrect operand type for operator or function; operator: ADD, operand type: %s", typeOf(a.value))
+			}
+			if err := setPath(out, a.path, copyValue(a.value)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := addValues(current, a.value)
+		if err != nil {
+			return nil, err
+		}
+		if err := setPath(out, a.path, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range u.delete {
+		current, exists := getPath(out, a.path)
+		if !exists {
+			continue
+		}
+		value, err := deleteElements(current, a.value)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			removePath(out, a.path)
+		} else if err := setPath(out, a.path, value); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func addValues(current, value types.AttributeValue) (types.AttributeValue, error) {
+	switch x := current.(type) {
+	case *types.AttributeValueMemberN:
+		if y, ok := value.(*types.AttributeValueMemberN); ok {
+			a, _ := parseNumber(x.Value)
+			b, _ := parseNumber(y.Value)
+			return &types.AttributeValueMemberN{Value: formatNumber(a.Add(a, b))}, nil
+		}
+	case *types.AttributeValueMemberSS:
+		if y, ok := value.(*types.AttributeValueMemberSS); ok {
+			return &types.AttributeValueMemberSS{Value: union(x.Value, y.Value, strings.Compare)}, nil
+		}
+	case *types.AttributeValueMemberNS:
+		if y, ok := value.(*types.AttributeValueMemberNS); ok {
+			return &types.AttributeValueMemberNS{Value: union(x.Value, y.Value, compareNumbers)}, nil
+		}
+	case *types.AttributeValueMemberBS:
+		if y, ok := value.(*types.AttributeValueMemberBS); ok {
+			return &types.AttributeValueMemberBS{Value: union(x.Value, y.Value, bytes.Compare)}, nil
+		}
+	}
+	return nil, fmt.Errorf("An operand in the update expression has an incorrect data type")
+}
+
+// deleteElements removes the elements of value from the set current. Returns nil when the set becomes empty
+func deleteElements(current, value types.AttributeValue) (types.AttributeValue, error) {
+	switch x := current.(type) {
+	case *types.AttributeValueMemberSS:
+		if y, ok := value.(*types.AttributeValueMemberSS); ok {
+			if rest := difference(x.Value, y.Value, strings.Compare); len(rest) > 0 {
+				return &types.AttributeValueMemberSS{Value: rest}, nil
+			}
+			return nil, nil
+		}
+	case *types.AttributeValueMemberNS:
+		if y, ok := value.(*types.AttributeValueMemberNS); ok {
+			if rest := difference(x.Value, y.Value, compareNumbers); len(rest) > 0 {
+				return &types.AttributeValueMemberNS{Value: rest}, nil
+			}
+			return nil, nil
+		}
+	case *types.AttributeValueMemberBS:
+		if y, ok := value.(*types.AttributeValueMemberBS); ok {
+			if rest := difference(x.Value, y.Value, bytes.Compare); len(rest) > 0 {
+				return &types.AttributeValueMemberBS{Value: rest}, nil
+			}
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("An operand in the update expression has an incorrect data type")
+}
+
+func union[E any](a, b []E, cmp func(E, E) int) []E {
+	out := append([]E(nil), a...)
+	for _, e := range b {
+		if !containsElement(out, e, cmp) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func difference[E any](a, b []E, cmp func(E, E) int) []E {
+	var out []E
+	for _, e := range a {
+		if !containsElement(b, e, cmp) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// project returns a copy of in limited to the given paths. Nested paths keep their surrounding maps and lists
+func project(in item, paths []path) item {
+	if paths == nil {
+		return copyItem(in)
+	}
+	out := item{}
+	for _, p := range paths {
+		v, ok := getPath(in, p)
+		if !ok {
+			continue
+		}
+		projectPath(out, p, copyValue(v))
+	}
+	return out
+}
+
+func projectPath(out item, p path, value types.AttributeValue) {
+	var current types.AttributeValue = &types.AttributeValueMemberM{Value: out}
+	for i, e := range p {
+		last := i == len(p)-1
+		switch v := current.(type) {
+		case *types.AttributeValueMemberM:
+			if last {
+				v.Value[e.name] = value
+				return
+			}
+			next, ok := v.Value[e.name]
+			if !ok {
+				if p[i+1].list {
+					next = &types.AttributeValueMemberL{}
+				} else {
+					next = &types.AttributeValueMemberM{Value: item{}}
+				}
+				v.Value[e.name] = next
+			}
+			current = next
+		case *types.AttributeValueMemberL:
+			// projected list elements are compacted in the order they are requested
+			if last {
+				v.Value = append(v.Value, value)
+				return
+			}
+			var next types.AttributeValue
+			if p[i+1].list {
+				next = &types.AttributeValueMemberL{}
+			} else {
+				next = &types.AttributeValueMemberM{Value: item{}}
+			}
+			v.Value = append(v.Value, next)
+			current = next
+		}
+	}
+}
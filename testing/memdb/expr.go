@@ -0,0 +1,881 @@
+package memdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenName  // #name placeholder
+	tokenValue // :value placeholder
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	isWord := func(c byte) bool {
+		return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || c == ':':
+			j := i + 1
+			for j < len(expr) && isWord(expr[j]) {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("Invalid %s: Syntax error; token: \"%c\", near: \"%s\"", "expression", c, expr[i:])
+			}
+			kind := tokenName
+			if c == ':' {
+				kind = tokenValue
+			}
+			tokens = append(tokens, token{kind, expr[i:j]})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(expr) && expr[j] >= '0' && expr[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, expr[i:j]})
+			i = j
+		case isWord(c):
+			j := i
+			for j < len(expr) && isWord(expr[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, expr[i:j]})
+			i = j
+		case c == '<' || c == '>':
+			if i+1 < len(expr) && (expr[i+1] == '=' || c == '<' && expr[i+1] == '>') {
+				tokens = append(tokens, token{tokenSymbol, expr[i : i+2]})
+				i += 2
+			} else {
+				tokens = append(tokens, token{tokenSymbol, expr[i : i+1]})
+				i++
+			}
+		case strings.IndexByte("()[],.=+-", c) >= 0:
+			tokens = append(tokens, token{tokenSymbol, expr[i : i+1]})
+			i++
+		default:
+			return nil, fmt.Errorf("Invalid expression: Syntax error; token: \"%c\", near: \"%s\"", c, expr[i:])
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// pathElem is a single element of a document path, an attribute name or a list index
+type pathElem struct {
+	name  string
+	index int
+	list  bool
+}
+
+type path []pathElem
+
+func (p path) String() string {
+	var b strings.Builder
+	for i, e := range p {
+		if e.list {
+			b.WriteString("[" + strconv.Itoa(e.index) + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(e.name)
+	}
+	return b.String()
+}
+
+// overlaps reports whether one path is a prefix of the other
+func (p path) overlaps(o path) bool {
+	for i := range min(len(p), len(o)) {
+		if p[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// operand evaluates to an attribute value; ok is false when the value does not exist
+type operand interface {
+	eval(in item) (v types.AttributeValue, ok bool)
+}
+
+type pathOperand struct{ path path }
+
+type valueOperand struct{ value types.AttributeValue }
+
+type sizeOperand struct{ path path }
+
+func (o pathOperand) eval(in item) (types.AttributeValue, bool) {
+	return getPath(in, o.path)
+}
+
+func (o valueOperand) eval(item) (types.AttributeValue, bool) {
+	return o.value, true
+}
+
+func (o sizeOperand) eval(in item) (types.AttributeValue, bool) {
+	v, ok := getPath(in, o.path)
+	if !ok {
+		return nil, false
+	}
+	size, ok := sizeFunction(v)
+	if !ok {
+		return nil, false
+	}
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(size)}, true
+}
+
+// condition is a boolean expression used by key conditions, filters and condition expressions
+type condition interface {
+	test(in item) (bool, error)
+}
+
+type andCondition struct{ left, right condition }
+type orCondition struct{ left, right condition }
+type notCondition struct{ cond condition }
+
+type compareCondition struct {
+	op          string
+	left, right operand
+}
+
+type betweenCondition struct{ operand, low, high operand }
+
+type inCondition struct {
+	operand operand
+	list    []operand
+}
+
+type functionCondition struct {
+	name string
+	path path
+	arg  operand
+}
+
+func (c andCondition) test(in item) (bool, error) {
+	ok, err := c.left.test(in)
+	if err != nil || !ok {
+		return false, err
+	}
+	return c.right.test(in)
+}
+
+func (c orCondition) test(in item) (bool, error) {
+	ok, err := c.left.test(in)
+	if err != nil || ok {
+		return ok, err
+	}
+	return c.right.test(in)
+}
+
+func (c notCondition) test(in item) (bool, error) {
+	ok, err := c.cond.test(in)
+	return !ok, err
+}
+
+func (c compareCondition) test(in item) (bool, error) {
+	left, lok := c.left.eval(in)
+	right, rok := c.right.eval(in)
+	switch c.op {
+	case "=":
+		return lok && rok && equalValues(left, right), nil
+	case "<>":
+		return !lok || !rok || !equalValues(left, right), nil
+	}
+	if !lok || !rok {
+		return false, nil
+	}
+	cmp, ok := compareValues(left, right)
+	if !ok {
+		return false, nil
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("Invalid expression: unknown comparator %s", c.op)
+}
+
+func (c betweenCondition) test(in item) (bool, error) {
+	v, ok := c.operand.eval(in)
+	low, lok := c.low.eval(in)
+	high, hok := c.high.eval(in)
+	if !ok || !lok || !hok {
+		return false, nil
+	}
+	if cmp, ok := compareValues(low, high); ok && cmp > 0 {
+		return false, fmt.Errorf("Invalid KeyConditionExpression: The BETWEEN operator requires upper bound to be greater than or equal to lower bound")
+	}
+	lc, lok := compareValues(v, low)
+	hc, hok := compareValues(v, high)
+	return lok && hok && lc >= 0 && hc <= 0, nil
+}
+
+func (c inCondition) test(in item) (bool, error) {
+	v, ok := c.operand.eval(in)
+	if !ok {
+		return false, nil
+	}
+	for _, o := range c.list {
+		if e, ok := o.eval(in); ok && equalValues(v, e) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c functionCondition) test(in item) (bool, error) {
+	v, exists := getPath(in, c.path)
+	switch c.name {
+	case "attribute_exists":
+		return exists, nil
+	case "attribute_not_exists":
+		return !exists, nil
+	}
+
+	arg, ok := c.arg.eval(in)
+	if !exists || !ok {
+		return false, nil
+	}
+	switch c.name {
+	case "attribute_type":
+		t, ok := arg.(*types.AttributeValueMemberS)
+		if !ok {
+			return false, fmt.Errorf("Invalid ConditionExpression: Incorrect operand type for operator or function; operator or function: attribute_type, operand type: %s", typeOf(arg))
+		}
+		return typeOf(v) == t.Value, nil
+	case "begins_with":
+		switch x := v.(type) {
+		case *types.AttributeValueMemberS:
+			p, ok := arg.(*types.AttributeValueMemberS)
+			return ok && strings.HasPrefix(x.Value, p.Value), nil
+		case *types.AttributeValueMemberB:
+			p, ok := arg.(*types.AttributeValueMemberB)
+			return ok && strings.HasPrefix(string(x.Value), string(p.Value)), nil
+		}
+		return false, nil
+	case "contains":
+		switch x := v.(type) {
+		case *types.AttributeValueMemberS:
+			s, ok := arg.(*types.AttributeValueMemberS)
+			return ok && strings.Contains(x.Value, s.Value), nil
+		case *types.AttributeValueMemberB:
+			s, ok := arg.(*types.AttributeValueMemberB)
+			return ok && strings.Contains(string(x.Value), string(s.Value)), nil
+		case *types.AttributeValueMemberSS:
+			s, ok := arg.(*types.AttributeValueMemberS)
+			return ok && containsElement(x.Value, s.Value, strings.Compare), nil
+		case *types.AttributeValueMemberNS:
+			n, ok := arg.(*types.AttributeValueMemberN)
+			return ok && containsElement(x.Value, n.Value, compareNumbers), nil
+		case *types.AttributeValueMemberBS:
+			b, ok := arg.(*types.AttributeValueMemberB)
+			return ok && containsElement(x.Value, b.Value, func(a, b []byte) int { return strings.Compare(string(a), string(b)) }), nil
+		case *types.AttributeValueMemberL:
+			for _, e := range x.Value {
+				if equalValues(e, arg) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("Invalid expression: unknown function %s", c.name)
+}
+
+// parser parses DynamoDB expressions. Placeholders are resolved against names and values;
+// used placeholders are tracked so unused ones can be reported once every expression of a request was parsed
+type parser struct {
+	names      map[string]string
+	values     map[string]types.AttributeValue
+	usedNames  map[string]bool
+	usedValues map[string]bool
+
+	kind   string
+	tokens []token
+	pos    int
+}
+
+func newParser(names map[string]string, values map[string]types.AttributeValue) *parser {
+	return &parser{
+		names:      names,
+		values:     values,
+		usedNames:  map[string]bool{},
+		usedValues: map[string]bool{},
+	}
+}
+
+// checkUnused fails when a placeholder was provided but not referenced by any expression
+func (p *parser) checkUnused() error {
+	if p.values != nil && len(p.values) == 0 {
+		return fmt.Errorf("ExpressionAttributeValues must not be empty")
+	}
+	if p.names != nil && len(p.names) == 0 {
+		return fmt.Errorf("ExpressionAttributeNames must not be empty")
+	}
+	for name := range p.names {
+		if !p.usedNames[name] {
+			return fmt.Errorf("Value provided in ExpressionAttributeNames unused in expressions: keys: {%s}", name)
+		}
+	}
+	for name := range p.values {
+		if !p.usedValues[name] {
+			return fmt.Errorf("Value provided in ExpressionAttributeValues unused in expressions: keys: {%s}", name)
+		}
+	}
+	return nil
+}
+
+func (p *parser) reset(kind, expr string) error {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return fmt.Errorf("Invalid %s: %w", kind, err)
+	}
+	p.kind, p.tokens, p.pos = kind, tokens, 0
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isSymbol(s string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.text == s
+}
+
+func (p *parser) isKeyword(k string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.text, k)
+}
+
+func (p *parser) isFunction(name string) bool {
+	t := p.peek()
+	n := p.tokens[min(p.pos+1, len(p.tokens)-1)]
+	return t.kind == tokenIdent && t.text == name && n.kind == tokenSymbol && n.text == "("
+}
+
+func (p *parser) expectSymbol(s string) error {
+	if !p.isSymbol(s) {
+		return p.syntaxError()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) syntaxError() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("Invalid %s: Syntax error; token: \"<EOF>\", near: \"\"", p.kind)
+	}
+	return fmt.Errorf("Invalid %s: Syntax error; token: \"%s\"", p.kind, t.text)
+}
+
+func (p *parser) expectEOF() error {
+	if p.peek().kind != tokenEOF {
+		return p.syntaxError()
+	}
+	return nil
+}
+
+func (p *parser) parsePath() (path, error) {
+	var result path
+	for {
+		t := p.next()
+		switch t.kind {
+		case tokenIdent:
+			if isReserved(t.text) {
+				return nil, fmt.Errorf("Invalid %s: Attribute name is a reserved keyword; reserved keyword: %s", p.kind, t.text)
+			}
+			result = append(result, pathElem{name: t.text})
+		case tokenName:
+			name, ok := p.names[t.text]
+			if !ok {
+				return nil, fmt.Errorf("Invalid %s: An expression attribute name used in the document path is not defined; attribute name: %s", p.kind, t.text)
+			}
+			p.usedNames[t.text] = true
+			result = append(result, pathElem{name: name})
+		default:
+			p.pos--
+			return nil, p.syntaxError()
+		}
+
+		for p.isSymbol("[") {
+			p.next()
+			n := p.next()
+			if n.kind != tokenNumber {
+				p.pos--
+				return nil, p.syntaxError()
+			}
+			idx, _ := strconv.Atoi(n.text)
+			result = append(result, pathElem{index: idx, list: true})
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+		}
+		if !p.isSymbol(".") {
+			return result, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseValue() (types.AttributeValue, error) {
+	t := p.next()
+	if t.kind != tokenValue {
+		p.pos--
+		return nil, p.syntaxError()
+	}
+	v, ok := p.values[t.text]
+	if !ok {
+		return nil, fmt.Errorf("Invalid %s: An expression attribute value used in expression is not defined; attribute value: %s", p.kind, t.text)
+	}
+	p.usedValues[t.text] = true
+	return v, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	switch {
+	case p.peek().kind == tokenValue:
+		v, err := p.parseValue()
+		return valueOperand{v}, err
+	case p.isFunction("size"):
+		p.next()
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return sizeOperand{path}, p.expectSymbol(")")
+	}
+	path, err := p.parsePath()
+	return pathOperand{path}, err
+}
+
+// parseCondition parses a complete condition expression
+func (p *parser) parseCondition(kind, expr string) (condition, error) {
+	if err := p.reset(kind, expr); err != nil {
+		return nil, err
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return cond, p.expectEOF()
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (condition, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		cond, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{cond}, nil
+	}
+	return p.parsePrimary()
+}
+
+var conditionFunctions = []string{"attribute_exists", "attribute_not_exists", "attribute_type", "begins_with", "contains"}
+
+func (p *parser) parsePrimary() (condition, error) {
+	if p.isSymbol("(") {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return cond, p.expectSymbol(")")
+	}
+
+	for _, name := range conditionFunctions {
+		if !p.isFunction(name) {
+			continue
+		}
+		p.next()
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		fn := functionCondition{name: name, path: path}
+		if name != "attribute_exists" && name != "attribute_not_exists" {
+			if err := p.expectSymbol(","); err != nil {
+				return nil, err
+			}
+			if fn.arg, err = p.parseOperand(); err != nil {
+				return nil, err
+			}
+		}
+		return fn, p.expectSymbol(")")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokenSymbol && strings.Contains("= <> < <= > >=", t.text) && t.text != "":
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareCondition{op: t.text, left: left, right: right}, nil
+	case p.isKeyword("BETWEEN"):
+		p.next()
+		low, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("AND") {
+			return nil, p.syntaxError()
+		}
+		p.next()
+		high, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return betweenCondition{left, low, high}, nil
+	case p.isKeyword("IN"):
+		p.next()
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		in := inCondition{operand: left}
+		for {
+			o, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			in.list = append(in.list, o)
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+		return in, p.expectSymbol(")")
+	}
+	return nil, p.syntaxError()
+}
+
+// parseProjection parses a comma separated list of document paths
+func (p *parser) parseProjection(expr string) ([]path, error) {
+	if err := p.reset("ProjectionExpression", expr); err != nil {
+		return nil, err
+	}
+	var paths []path
+	for {
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return paths, p.expectEOF()
+}
+
+// update expression AST
+
+type setValue interface {
+	value(in item) (types.AttributeValue, error)
+}
+
+type operandValue struct{ operand operand }
+
+type ifNotExistsValue struct {
+	path     path
+	fallback setValue
+}
+
+type listAppendValue struct{ left, right setValue }
+
+type arithmeticValue struct {
+	op          string
+	left, right setValue
+}
+
+func (v operandValue) value(in item) (types.AttributeValue, error) {
+	value, ok := v.operand.eval(in)
+	if !ok {
+		return nil, fmt.Errorf("The provided expression refers to an attribute that does not exist in the item")
+	}
+	return value, nil
+}
+
+func (v ifNotExistsValue) value(in item) (types.AttributeValue, error) {
+	if value, ok := getPath(in, v.path); ok {
+		return value, nil
+	}
+	return v.fallback.value(in)
+}
+
+func (v listAppendValue) value(in item) (types.AttributeValue, error) {
+	left, err := v.left.value(in)
+	if err != nil {
+		return nil, err
+	}
+	right, err := v.right.value(in)
+	if err != nil {
+		return nil, err
+	}
+	l, lok := left.(*types.AttributeValueMemberL)
+	r, rok := right.(*types.AttributeValueMemberL)
+	if !lok || !rok {
+		return nil, fmt.Errorf("An operand in the update expression has an incorrect data type")
+	}
+	values := append(append([]types.AttributeValue{}, l.Value...), r.Value...)
+	return &types.AttributeValueMemberL{Value: values}, nil
+}
+
+func (v arithmeticValue) value(in item) (types.AttributeValue, error) {
+	left, err := v.left.value(in)
+	if err != nil {
+		return nil, err
+	}
+	right, err := v.right.value(in)
+	if err != nil {
+		return nil, err
+	}
+	l, lok := left.(*types.AttributeValueMemberN)
+	r, rok := right.(*types.AttributeValueMemberN)
+	if !lok || !rok {
+		return nil, fmt.Errorf("An operand in the update expression has an incorrect data type")
+	}
+	x, _ := parseNumber(l.Value)
+	y, _ := parseNumber(r.Value)
+	if v.op == "-" {
+		y.Neg(y)
+	}
+	return &types.AttributeValueMemberN{Value: formatNumber(x.Add(x, y))}, nil
+}
+
+type setAction struct {
+	path  path
+	value setValue
+}
+
+type setElementAction struct {
+	path  path
+	value types.AttributeValue
+}
+
+type updateExpression struct {
+	set    []setAction
+	remove []path
+	add    []setElementAction
+	delete []setElementAction
+}
+
+// paths returns every path modified by the update
+func (u *updateExpression) paths() []path {
+	var paths []path
+	for _, a := range u.set {
+		paths = append(paths, a.path)
+	}
+	paths = append(paths, u.remove...)
+	for _, a := range u.add {
+		paths = append(paths, a.path)
+	}
+	for _, a := range u.delete {
+		paths = append(paths, a.path)
+	}
+	return paths
+}
+
+func (p *parser) parseUpdate(expr string) (*updateExpression, error) {
+	if err := p.reset("UpdateExpression", expr); err != nil {
+		return nil, err
+	}
+	update := &updateExpression{}
+	seen := map[string]bool{}
+	for p.peek().kind != tokenEOF {
+		t := p.next()
+		clause := strings.ToUpper(t.text)
+		if t.kind != tokenIdent || (clause != "SET" && clause != "REMOVE" && clause != "ADD" && clause != "DELETE") {
+			p.pos--
+			return nil, p.syntaxError()
+		}
+		if seen[clause] {
+			return nil, fmt.Errorf("Invalid UpdateExpression: The \"%s\" section can only be used once in an update expression;", clause)
+		}
+		seen[clause] = true
+
+		for {
+			path, err := p.parsePath()
+			if err != nil {
+				return nil, err
+			}
+			switch clause {
+			case "SET":
+				if err := p.expectSymbol("="); err != nil {
+					return nil, err
+				}
+				value, err := p.parseSetValue()
+				if err != nil {
+					return nil, err
+				}
+				update.set = append(update.set, setAction{path, value})
+			case "REMOVE":
+				update.remove = append(update.remove, path)
+			case "ADD", "DELETE":
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				if clause == "ADD" {
+					update.add = append(update.add, setElementAction{path, value})
+				} else {
+					update.delete = append(update.delete, setElementAction{path, value})
+				}
+			}
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if len(seen) == 0 {
+		return nil, p.syntaxError()
+	}
+
+	paths := update.paths()
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			if paths[i].overlaps(paths[j]) {
+				return nil, fmt.Errorf("Invalid UpdateExpression: Two document paths overlap with each other; must remove or rewrite one of these paths; path one: [%s], path two: [%s]", paths[i], paths[j])
+			}
+		}
+	}
+	return update, nil
+}
+
+func (p *parser) parseSetValue() (setValue, error) {
+	left, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.isSymbol("+") || p.isSymbol("-") {
+		op := p.next().text
+		right, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		return arithmeticValue{op, left, right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseSetOperand() (setValue, error) {
+	switch {
+	case p.isFunction("if_not_exists"):
+		p.next()
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+		fallback, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		return ifNotExistsValue{path, fallback}, p.expectSymbol(")")
+	case p.isFunction("list_append"):
+		p.next()
+		p.next()
+		left, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+		right, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		return listAppendValue{left, right}, p.expectSymbol(")")
+	}
+	o, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := o.(sizeOperand); ok {
+		return nil, fmt.Errorf("Invalid UpdateExpression: The function is not allowed in an update expression; function: size")
+	}
+	return operandValue{o}, nil
+}
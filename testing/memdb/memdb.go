@@ -0,0 +1,645 @@
+// Package memdb is an in-memory implementation of the DynamoDB API used by dynago.Client.
+// It is intended for unit tests that should not depend on Docker or a running DynamoDB instance.
+//
+// Items are ordered by partition and sort key like DynamoDB, and key conditions, filters,
+// condition and update expressions, projections, pagination, batches and transactions are supported.
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+const (
+	// MaxItemSize is the maximum size of an item in bytes
+	MaxItemSize = dynago.MaxItemSize
+	// MaxPageSize is the maximum size of the items read by a single Query or Scan request
+	MaxPageSize = 1024 * 1024
+)
+
+var _ dynago.DynamoDBAPI = (*DB)(nil)
+
+// DB is an in-memory DynamoDB database. It is safe for concurrent use
+type DB struct {
+	mu     sync.Mutex
+	tables map[string]*table
+	// client request tokens of successful transactions
+	tokens map[string]bool
+}
+
+// New creates an empty database
+//
+//	db := memdb.New()
+//	err := db.CreateTable(ctx, "test", "pk", "sk")
+//	table := db.NewClient(dynago.ClientOptions{TableName: "test", PartitionKeyName: "pk", SortKeyName: "sk"})
+func New() *DB {
+	return &DB{tables: map[string]*table{}, tokens: map[string]bool{}}
+}
+
+// NewClient creates a dynago client that reads and writes to this database. The client implements
+// dynago.DynamoClient, so it can replace the client of code depending on that interface in tests
+func (db *DB) NewClient(opt dynago.ClientOptions) *dynago.Client {
+	return dynago.NewClientFromAPI(db, opt)
+}
+
+// TableOption customises the table created by CreateTable
+type TableOption func(*dynamodb.CreateTableInput)
+
+// WithKeyTypes sets the attribute types of the partition and sort keys, empty types keep the default string type.
+// The sort key type is ignored for tables without a sort key
+func WithKeyTypes(pk, sk types.ScalarAttributeType) TableOption {
+	return func(input *dynamodb.CreateTableInput) {
+		if pk != "" {
+			input.AttributeDefinitions[0].AttributeType = pk
+		}
+		if sk != "" && len(input.AttributeDefinitions) > 1 {
+			input.AttributeDefinitions[1].AttributeType = sk
+		}
+	}
+}
+
+// CreateTable creates a table with string partition and sort keys, mirroring localdb.TestDatabase.CreateTable.
+// A table without sort key is created when sk is empty, use WithKeyTypes for numeric or binary keys
+func (db *DB) CreateTable(ctx context.Context, tableName, pk, sk string, opts ...TableOption) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: &tableName,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: &pk, AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: &pk, KeyType: types.KeyTypeHash},
+		},
+	}
+	if sk != "" {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{AttributeName: &sk, AttributeType: types.ScalarAttributeTypeS})
+		input.KeySchema = append(input.KeySchema, types.KeySchemaElement{AttributeName: &sk, KeyType: types.KeyTypeRange})
+	}
+	for _, opt := range opts {
+		opt(input)
+	}
+	return db.CreateTableFromInput(ctx, input)
+}
+
+// CreateTableFromInput creates a table from a DynamoDB CreateTable request.
+// Key types and global and local secondary indexes with their projections are supported, other settings are ignored
+func (db *DB) CreateTableFromInput(ctx context.Context, input *dynamodb.CreateTableInput) error {
+	def, err := parseTableDefinition(input)
+	if err != nil {
+		return operationError("CreateTable", err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.tables[def.name]; ok {
+		return operationError("CreateTable", &types.ResourceInUseException{Message: aws.String("Cannot create preexisting table")})
+	}
+	db.tables[def.name] = newTable(def)
+	return nil
+}
+
+// DeleteTable removes a table and all of its items
+func (db *DB) DeleteTable(ctx context.Context, tableName string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.tables[tableName]; !ok {
+		return operationError("DeleteTable", resourceNotFound(tableName))
+	}
+	delete(db.tables, tableName)
+	return nil
+}
+
+func parseTableDefinition(input *dynamodb.CreateTableInput) (*tableDefinition, error) {
+	if input.TableName == nil || *input.TableName == "" {
+		return nil, validationError("TableName must not be empty")
+	}
+	attrs := map[string]types.ScalarAttributeType{}
+	for _, a := range input.AttributeDefinitions {
+		if a.AttributeName == nil {
+			return nil, validationError("AttributeName must not be empty")
+		}
+		switch a.AttributeType {
+		case types.ScalarAttributeTypeS, types.ScalarAttributeTypeN, types.ScalarAttributeTypeB:
+		default:
+			return nil, validationError(fmt.Sprintf("Invalid AttributeType %s for attribute %s", a.AttributeType, *a.AttributeName))
+		}
+		attrs[*a.AttributeName] = a.AttributeType
+	}
+	key, err := parseKeySchema(input.KeySchema, attrs)
+	if err != nil {
+		return nil, err
+	}
+	def := &tableDefinition{name: *input.TableName, key: key, indexes: map[string]*index{}}
+
+	addIndex := func(name *string, schema []types.KeySchemaElement, projection *types.Projection, global bool) error {
+		if name == nil || *name == "" {
+			return validationError("IndexName must not be empty")
+		}
+		if _, ok := def.indexes[*name]; ok {
+			return validationError(fmt.Sprintf("Duplicate index name: %s", *name))
+		}
+		k, err := parseKeySchema(schema, attrs)
+		if err != nil {
+			return err
+		}
+		if !global && k.hash != key.hash {
+			return validationError(fmt.Sprintf("Table KeySchema does not have a range key, which is required when specifying a LocalSecondaryIndex; index: %s", *name))
+		}
+		idx := &index{name: *name, key: k, global: global}
+		idx.projection, idx.include = parseProjection(projection)
+		def.indexes[*name] = idx
+		return nil
+	}
+	for _, gsi := range input.GlobalSecondaryIndexes {
+		if err := addIndex(gsi.IndexName, gsi.KeySchema, gsi.Projection, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, lsi := range input.LocalSecondaryIndexes {
+		if err := addIndex(lsi.IndexName, lsi.KeySchema, lsi.Projection, false); err != nil {
+			return nil, err
+		}
+	}
+	return def, nil
+}
+
+// table returns the table with the given name. Must be called with db.mu held
+func (db *DB) table(name *string) (*table, error) {
+	if name == nil || *name == "" {
+		return nil, validationError("TableName must not be empty")
+	}
+	t, ok := db.tables[*name]
+	if !ok {
+		return nil, resourceNotFound(*name)
+	}
+	return t, nil
+}
+
+// GetItem implements dynago.DynamoDBAPI
+func (db *DB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("GetItem", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.getItem(params)
+	return out, operationError("GetItem", err)
+}
+
+func (db *DB) getItem(params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	t, err := db.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.key.validateKey(params.Key); err != nil {
+		return nil, err
+	}
+	p := newParser(params.ExpressionAttributeNames, nil)
+	paths, err := parseOptionalProjection(p, params.ProjectionExpression)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkUnused(); err != nil {
+		return nil, validationError(err.Error())
+	}
+
+	out := &dynamodb.GetItemOutput{}
+	if in, ok := t.get(params.Key); ok {
+		out.Item = project(in, paths)
+		out.ConsumedCapacity = readCapacity(params.ReturnConsumedCapacity, t.name, itemSize(in), params.ConsistentRead)
+	} else {
+		out.ConsumedCapacity = readCapacity(params.ReturnConsumedCapacity, t.name, 0, params.ConsistentRead)
+	}
+	return out, nil
+}
+
+// PutItem implements dynago.DynamoDBAPI
+func (db *DB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("PutItem", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	w, err := db.preparePut(params.TableName, params.Item, params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, operationError("PutItem", err)
+	}
+	switch params.ReturnValues {
+	case "", types.ReturnValueNone, types.ReturnValueAllOld:
+	default:
+		return nil, operationError("PutItem", validationError("ReturnValues can only be ALL_OLD or NONE"))
+	}
+	if err := w.check(params.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, operationError("PutItem", err)
+	}
+	w.apply()
+
+	out := &dynamodb.PutItemOutput{ConsumedCapacity: writeCapacity(params.ReturnConsumedCapacity, w.table.name, w.size())}
+	if params.ReturnValues == types.ReturnValueAllOld && w.old != nil {
+		out.Attributes = copyItem(w.old)
+	}
+	return out, nil
+}
+
+// UpdateItem implements dynago.DynamoDBAPI
+func (db *DB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("UpdateItem", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	w, err := db.prepareUpdate(params.TableName, params.Key, params.UpdateExpression, params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, operationError("UpdateItem", err)
+	}
+	if err := w.check(params.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, operationError("UpdateItem", err)
+	}
+	w.apply()
+
+	out := &dynamodb.UpdateItemOutput{ConsumedCapacity: writeCapacity(params.ReturnConsumedCapacity, w.table.name, w.size())}
+	switch params.ReturnValues {
+	case "", types.ReturnValueNone:
+	case types.ReturnValueAllOld:
+		out.Attributes = copyItem(w.old)
+	case types.ReturnValueAllNew:
+		out.Attributes = copyItem(w.new)
+	case types.ReturnValueUpdatedOld:
+		out.Attributes = w.updated(w.old)
+	case types.ReturnValueUpdatedNew:
+		out.Attributes = w.updated(w.new)
+	default:
+		return nil, operationError("UpdateItem", validationError(fmt.Sprintf("Invalid ReturnValues %s", params.ReturnValues)))
+	}
+	return out, nil
+}
+
+// DeleteItem implements dynago.DynamoDBAPI
+func (db *DB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("DeleteItem", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	w, err := db.prepareDelete(params.TableName, params.Key, params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, operationError("DeleteItem", err)
+	}
+	switch params.ReturnValues {
+	case "", types.ReturnValueNone, types.ReturnValueAllOld:
+	default:
+		return nil, operationError("DeleteItem", validationError("ReturnValues can only be ALL_OLD or NONE"))
+	}
+	if err := w.check(params.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, operationError("DeleteItem", err)
+	}
+	w.apply()
+
+	out := &dynamodb.DeleteItemOutput{ConsumedCapacity: writeCapacity(params.ReturnConsumedCapacity, w.table.name, w.size())}
+	if params.ReturnValues == types.ReturnValueAllOld && w.old != nil {
+		out.Attributes = copyItem(w.old)
+	}
+	return out, nil
+}
+
+// BatchGetItem implements dynago.DynamoDBAPI. All keys are processed, UnprocessedKeys is always empty
+func (db *DB) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("BatchGetItem", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.batchGetItem(params)
+	return out, operationError("BatchGetItem", err)
+}
+
+func (db *DB) batchGetItem(params *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	count := 0
+	for _, req := range params.RequestItems {
+		count += len(req.Keys)
+	}
+	if count == 0 {
+		return nil, validationError("1 validation error detected: Value at 'requestItems' failed to satisfy constraint: Map value must satisfy constraint: Member must have length greater than or equal to 1")
+	}
+	if count > 100 {
+		return nil, validationError("Too many items requested for the BatchGetItem call")
+	}
+
+	out := &dynamodb.BatchGetItemOutput{
+		Responses:       map[string][]map[string]types.AttributeValue{},
+		UnprocessedKeys: map[string]types.KeysAndAttributes{},
+	}
+	for name, req := range params.RequestItems {
+		t, err := db.table(&name)
+		if err != nil {
+			return nil, err
+		}
+		p := newParser(req.ExpressionAttributeNames, nil)
+		paths, err := parseOptionalProjection(p, req.ProjectionExpression)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkUnused(); err != nil {
+			return nil, validationError(err.Error())
+		}
+
+		seen := map[string]bool{}
+		size := 0
+		responses := []map[string]types.AttributeValue{}
+		for _, key := range req.Keys {
+			if err := t.key.validateKey(key); err != nil {
+				return nil, err
+			}
+			id := keyString(key, t.key.names()...)
+			if seen[id] {
+				return nil, validationError("Provided list of item keys contains duplicates")
+			}
+			seen[id] = true
+			if in, ok := t.get(key); ok {
+				responses = append(responses, project(in, paths))
+				size += itemSize(in)
+			}
+		}
+		out.Responses[name] = responses
+		if c := readCapacity(params.ReturnConsumedCapacity, name, size, req.ConsistentRead); c != nil {
+			out.ConsumedCapacity = append(out.ConsumedCapacity, *c)
+		}
+	}
+	return out, nil
+}
+
+// BatchWriteItem implements dynago.DynamoDBAPI. All requests are validated before any is applied,
+// UnprocessedItems is always empty
+func (db *DB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("BatchWriteItem", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.batchWriteItem(params)
+	return out, operationError("BatchWriteItem", err)
+}
+
+func (db *DB) batchWriteItem(params *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	count := 0
+	for _, requests := range params.RequestItems {
+		count += len(requests)
+	}
+	if count == 0 {
+		return nil, validationError("1 validation error detected: Value at 'requestItems' failed to satisfy constraint: Map value must satisfy constraint: Member must have length greater than or equal to 1")
+	}
+	if count > 25 {
+		return nil, validationError("Too many items requested for the BatchWriteItem call")
+	}
+
+	var writes []*write
+	for name, requests := range params.RequestItems {
+		seen := map[string]bool{}
+		for _, req := range requests {
+			var w *write
+			var err error
+			switch {
+			case req.PutRequest != nil && req.DeleteRequest == nil:
+				w, err = db.preparePut(&name, req.PutRequest.Item, nil, nil, nil)
+			case req.DeleteRequest != nil && req.PutRequest == nil:
+				w, err = db.prepareDelete(&name, req.DeleteRequest.Key, nil, nil, nil)
+			default:
+				err = validationError("Supplied AttributeValue has more than one datatypes set, must contain exactly one of the supported datatypes")
+			}
+			if err != nil {
+				return nil, err
+			}
+			id := keyString(w.key, w.table.key.names()...)
+			if seen[id] {
+				return nil, validationError("Provided list of item keys contains duplicates")
+			}
+			seen[id] = true
+			writes = append(writes, w)
+		}
+	}
+
+	out := &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}
+	capacity := map[string]float64{}
+	for _, w := range writes {
+		w.apply()
+		capacity[w.table.name] += writeUnits(w.size())
+	}
+	out.ConsumedCapacity = tableCapacity(params.ReturnConsumedCapacity, capacity)
+	return out, nil
+}
+
+// TransactWriteItems implements dynago.DynamoDBAPI. Either every action is applied or none.
+// Requests with a ClientRequestToken of a previous successful transaction succeed without changes
+func (db *DB) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("TransactWriteItems", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.transactWriteItems(params)
+	return out, operationError("TransactWriteItems", err)
+}
+
+func (db *DB) transactWriteItems(params *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	if len(params.TransactItems) == 0 {
+		return nil, validationError("1 validation error detected: Value null at 'transactItems' failed to satisfy constraint: Member must not be null")
+	}
+	if len(params.TransactItems) > 100 {
+		return nil, validationError("1 validation error detected: Value at 'transactItems' failed to satisfy constraint: Member must have length less than or equal to 100")
+	}
+	if params.ClientRequestToken != nil && db.tokens[*params.ClientRequestToken] {
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	}
+
+	writes := make([]*write, len(params.TransactItems))
+	seen := map[string]bool{}
+	for i, action := range params.TransactItems {
+		var w *write
+		var err error
+		actions := 0
+		if c := action.ConditionCheck; c != nil {
+			actions++
+			if c.ConditionExpression == nil {
+				return nil, validationError("The ConditionExpression of a ConditionCheck must not be empty")
+			}
+			w, err = db.prepareCheck(c.TableName, c.Key, c.ConditionExpression, c.ExpressionAttributeNames, c.ExpressionAttributeValues)
+			if w != nil {
+				w.returnOld = c.ReturnValuesOnConditionCheckFailure
+			}
+		}
+		if p := action.Put; p != nil {
+			actions++
+			w, err = db.preparePut(p.TableName, p.Item, p.ConditionExpression, p.ExpressionAttributeNames, p.ExpressionAttributeValues)
+			if w != nil {
+				w.returnOld = p.ReturnValuesOnConditionCheckFailure
+			}
+		}
+		if d := action.Delete; d != nil {
+			actions++
+			w, err = db.prepareDelete(d.TableName, d.Key, d.ConditionExpression, d.ExpressionAttributeNames, d.ExpressionAttributeValues)
+			if w != nil {
+				w.returnOld = d.ReturnValuesOnConditionCheckFailure
+			}
+		}
+		if u := action.Update; u != nil {
+			actions++
+			w, err = db.prepareUpdate(u.TableName, u.Key, u.UpdateExpression, u.ConditionExpression, u.ExpressionAttributeNames, u.ExpressionAttributeValues)
+			if w != nil {
+				w.returnOld = u.ReturnValuesOnConditionCheckFailure
+			}
+		}
+		if actions != 1 {
+			return nil, validationError("TransactItems can only contain one of Check, Put, Update or Delete")
+		}
+		if err != nil {
+			return nil, err
+		}
+		id := w.table.name + "/" + keyString(w.key, w.table.key.names()...)
+		if seen[id] {
+			return nil, validationError("Transaction request cannot include multiple operations on one item")
+		}
+		seen[id] = true
+		writes[i] = w
+	}
+
+	reasons := make([]types.CancellationReason, len(writes))
+	failed := false
+	for i, w := range writes {
+		reasons[i] = types.CancellationReason{Code: aws.String("None")}
+		err := w.check(w.returnOld)
+		if err == nil {
+			continue
+		}
+		failed = true
+		if !isConditionFailed(err) {
+			reasons[i] = types.CancellationReason{Code: aws.String("ValidationError"), Message: aws.String(err.Error())}
+			continue
+		}
+		reasons[i] = types.CancellationReason{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")}
+		if w.returnOld == types.ReturnValuesOnConditionCheckFailureAllOld && w.old != nil {
+			reasons[i].Item = copyItem(w.old)
+		}
+	}
+	if failed {
+		codes := make([]string, len(reasons))
+		for i, r := range reasons {
+			codes[i] = *r.Code
+		}
+		return nil, &types.TransactionCanceledException{
+			Message:             aws.String("Transaction cancelled, please refer cancellation reasons for specific reasons [" + strings.Join(codes, ", ") + "]"),
+			CancellationReasons: reasons,
+		}
+	}
+
+	capacity := map[string]float64{}
+	for _, w := range writes {
+		w.apply()
+		// transactional writes consume twice the capacity of standard writes
+		capacity[w.table.name] += 2 * writeUnits(w.size())
+	}
+	if params.ClientRequestToken != nil {
+		db.tokens[*params.ClientRequestToken] = true
+	}
+	return &dynamodb.TransactWriteItemsOutput{
+		ConsumedCapacity: tableCapacity(params.ReturnConsumedCapacity, capacity),
+	}, nil
+}
+
+// TransactGetItems implements dynago.DynamoDBAPI. Items are read from a consistent snapshot of the database
+func (db *DB) TransactGetItems(ctx context.Context, params *dynamodb.TransactGetItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactGetItemsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("TransactGetItems", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.transactGetItems(params)
+	return out, operationError("TransactGetItems", err)
+}
+
+func (db *DB) transactGetItems(params *dynamodb.TransactGetItemsInput) (*dynamodb.TransactGetItemsOutput, error) {
+	if len(params.TransactItems) == 0 {
+		return nil, validationError("1 validation error detected: Value null at 'transactItems' failed to satisfy constraint: Member must not be null")
+	}
+	if len(params.TransactItems) > 100 {
+		return nil, validationError("1 validation error detected: Value at 'transactItems' failed to satisfy constraint: Member must have length less than or equal to 100")
+	}
+
+	responses := make([]types.ItemResponse, len(params.TransactItems))
+	capacity := map[string]float64{}
+	seen := map[string]bool{}
+	for i, action := range params.TransactItems {
+		get := action.Get
+		if get == nil {
+			return nil, validationError("TransactItems must contain a Get")
+		}
+		t, err := db.table(get.TableName)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.key.validateKey(get.Key); err != nil {
+			return nil, err
+		}
+		id := t.name + "/" + keyString(get.Key, t.key.names()...)
+		if seen[id] {
+			return nil, validationError("Transaction request cannot include multiple operations on one item")
+		}
+		seen[id] = true
+
+		p := newParser(get.ExpressionAttributeNames, nil)
+		paths, err := parseOptionalProjection(p, get.ProjectionExpression)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkUnused(); err != nil {
+			return nil, validationError(err.Error())
+		}
+
+		size := 0
+		if in, ok := t.get(get.Key); ok {
+			responses[i].Item = project(in, paths)
+			size = itemSize(in)
+		}
+		// transactional reads consume twice the capacity of strongly consistent reads
+		capacity[t.name] += 2 * readUnits(size, aws.Bool(true))
+	}
+	return &dynamodb.TransactGetItemsOutput{
+		Responses:        responses,
+		ConsumedCapacity: tableCapacity(params.ReturnConsumedCapacity, capacity),
+	}, nil
+}
+
+func parseOptionalProjection(p *parser, expr *string) ([]path, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	paths, err := p.parseProjection(*expr)
+	if err != nil {
+		return nil, validationError(err.Error())
+	}
+	return paths, nil
+}
+
+// sortedKeys returns the keys of m in order, for deterministic iteration
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,280 @@
+package memdb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/testing/memdb"
+)
+
+type Order struct {
+	Pk      string `dynamodbav:"pk"`
+	Sk      string `dynamodbav:"sk"`
+	Status  string
+	Version int
+}
+
+func prepareTable(t *testing.T) (*memdb.DB, *dynago.Client) {
+	t.Helper()
+	db := memdb.New()
+	if err := db.CreateTable(context.TODO(), "test", "pk", "sk"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	return db, db.NewClient(dynago.ClientOptions{TableName: "test", PartitionKeyName: "pk", SortKeyName: "sk"})
+}
+
+func TestCreateTable(t *testing.T) {
+	db, _ := prepareTable(t)
+	var inUse *types.ResourceInUseException
+	if err := db.CreateTable(context.TODO(), "test", "pk", "sk"); !errors.As(err, &inUse) {
+		t.Errorf("expected existing table to be rejected; got %v", err)
+	}
+
+	_, err := db.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String("missing"),
+		Key:       map[string]types.AttributeValue{"pk": dynago.StringValue("a")},
+	})
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected missing table error; got %v", err)
+	}
+}
+
+func TestKeyOrdering(t *testing.T) {
+	ctx := context.TODO()
+	db := memdb.New()
+	err := db.CreateTableFromInput(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String("numbers"),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("n"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("n"), KeyType: types.KeyTypeRange},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	for _, n := range []string{"10", "-2.5", "3", "100", "0"} {
+		_, err := db.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String("numbers"),
+			Item:      map[string]types.AttributeValue{"pk": dynago.StringValue("a"), "n": &types.AttributeValueMemberN{Value: n}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	query := func(forward bool) []string {
+		out, err := db.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String("numbers"),
+			KeyConditionExpression:    aws.String("pk = :pk AND n >= :min"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":pk": dynago.StringValue("a"), ":min": &types.AttributeValueMemberN{Value: "0"}},
+			ScanIndexForward:          aws.Bool(forward),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var values []string
+		for _, item := range out.Items {
+			values = append(values, item["n"].(*types.AttributeValueMemberN).Value)
+		}
+		return values
+	}
+	if got, expected := query(true), []string{"0", "3", "10", "100"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected numeric sort key order %v; got %v", expected, got)
+	}
+	if got, expected := query(false), []string{"100", "10", "3", "0"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected reverse order %v; got %v", expected, got)
+	}
+}
+
+func TestConditionExpression(t *testing.T) {
+	_, table := prepareTable(t)
+	ctx := context.TODO()
+	pk, sk := dynago.StringValue("orders"), dynago.StringValue("order#1")
+
+	// optimistic locking: create only when missing, then update only when the version matches
+	create := func(input *dynamodb.PutItemInput) error {
+		input.ConditionExpression = aws.String("attribute_not_exists(pk)")
+		return nil
+	}
+	if err := table.PutItem(ctx, pk, sk, Order{Status: "new", Version: 1}, create); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	err := table.PutItem(ctx, pk, sk, Order{Status: "new", Version: 1}, create)
+	var ccf *types.ConditionalCheckFailedException
+	if !errors.As(err, &ccf) {
+		t.Errorf("expected conditional check failure; got %v", err)
+	}
+
+	update := dynago.NewUpdate().Set("Status", "paid").Add("Version", 1)
+	_, err = table.UpdateItem(ctx, pk, sk, update, dynago.WithUpdateCondition("Version = :v", map[string]dynago.Attribute{":v": dynago.NumberValue(1)}))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	_, err = table.UpdateItem(ctx, pk, sk, update, dynago.WithUpdateCondition("Version = :v", map[string]dynago.Attribute{":v": dynago.NumberValue(1)}))
+	if !errors.As(err, &ccf) {
+		t.Errorf("expected stale version to fail; got %v", err)
+	}
+
+	var out Order
+	err, found := table.GetItem(ctx, pk, sk, &out)
+	if err != nil || !found {
+		t.Fatalf("expected item to be found; got %v", err)
+	}
+	if out.Status != "paid" || out.Version != 2 {
+		t.Errorf("expected update to be applied once; got %v", out)
+	}
+}
+
+func TestQueryPagination(t *testing.T) {
+	db, table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("orders")
+	for idx := range 10 {
+		status := "open"
+		if idx%2 == 1 {
+			status = "closed"
+		}
+		if err := table.PutItem(ctx, pk, dynago.StringValue(fmt.Sprintf("order#%02d", idx)), Order{Status: status}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	var pages [][]string
+	var cursor map[string]types.AttributeValue
+	for {
+		out, err := db.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String("test"),
+			KeyConditionExpression:    aws.String("pk = :pk"),
+			FilterExpression:          aws.String("#status = :status"),
+			ExpressionAttributeNames:  map[string]string{"#status": "Status"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{":pk": pk, ":status": dynago.StringValue("open")},
+			Limit:                     aws.Int32(4),
+			ExclusiveStartKey:         cursor,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var page []string
+		for _, item := range out.Items {
+			page = append(page, item["sk"].(*types.AttributeValueMemberS).Value)
+		}
+		pages = append(pages, page)
+		if cursor = out.LastEvaluatedKey; cursor == nil {
+			break
+		}
+	}
+	// the limit is applied before the filter
+	expected := [][]string{{"order#00", "order#02"}, {"order#04", "order#06"}, {"order#08"}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("expected pages %v; got %v", expected, pages)
+	}
+}
+
+func TestTransactItems(t *testing.T) {
+	db, table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("orders")
+
+	if err := table.PutItem(ctx, pk, dynago.StringValue("order#1"), Order{Status: "new"}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	_, err := db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName: aws.String("test"),
+				Item:      map[string]types.AttributeValue{"pk": pk, "sk": dynago.StringValue("order#2")},
+			}},
+			{ConditionCheck: &types.ConditionCheck{
+				TableName:                 aws.String("test"),
+				Key:                       map[string]types.AttributeValue{"pk": pk, "sk": dynago.StringValue("order#1")},
+				ConditionExpression:       aws.String("#status = :status"),
+				ExpressionAttributeNames:  map[string]string{"#status": "Status"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{":status": dynago.StringValue("paid")},
+			}},
+		},
+	})
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		t.Fatalf("expected transaction to be canceled; got %v", err)
+	}
+	codes := []string{aws.ToString(canceled.CancellationReasons[0].Code), aws.ToString(canceled.CancellationReasons[1].Code)}
+	if !reflect.DeepEqual(codes, []string{"None", "ConditionalCheckFailed"}) {
+		t.Errorf("expected cancellation reason for each item; got %v", codes)
+	}
+
+	var out Order
+	if err, found := table.GetItem(ctx, pk, dynago.StringValue("order#2"), &out); err != nil || found {
+		t.Errorf("expected no item to be written by a canceled transaction; got %v %v", out, err)
+	}
+
+	err = table.TransactItems(ctx,
+		table.WithDeleteItem(dynago.StringValue("orders"), dynago.StringValue("order#1")),
+		table.WithDeleteItem(dynago.StringValue("orders"), dynago.StringValue("order#1")),
+	)
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+		t.Errorf("expected multiple operations on one item to be rejected; got %v", err)
+	}
+}
+
+func TestExpressionValidation(t *testing.T) {
+	db, _ := prepareTable(t)
+	ctx := context.TODO()
+	key := map[string]types.AttributeValue{"pk": dynago.StringValue("a"), "sk": dynago.StringValue("b")}
+
+	cases := []struct {
+		title string
+		input *dynamodb.UpdateItemInput
+	}{
+		{
+			title: "reserved word",
+			input: &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET Status = :s"), ExpressionAttributeValues: map[string]types.AttributeValue{":s": dynago.StringValue("x")}},
+		},
+		{
+			title: "undefined value",
+			input: &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET Name1 = :missing")},
+		},
+		{
+			title: "unused value",
+			input: &dynamodb.UpdateItemInput{UpdateExpression: aws.String("REMOVE Name1"), ExpressionAttributeValues: map[string]types.AttributeValue{":s": dynago.StringValue("x")}},
+		},
+		{
+			title: "key attribute",
+			input: &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET sk = :s"), ExpressionAttributeValues: map[string]types.AttributeValue{":s": dynago.StringValue("x")}},
+		},
+		{
+			title: "overlapping paths",
+			input: &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET Address.City = :s REMOVE Address"), ExpressionAttributeValues: map[string]types.AttributeValue{":s": dynago.StringValue("x")}},
+		},
+		{
+			title: "syntax error",
+			input: &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET Name1 = = :s"), ExpressionAttributeValues: map[string]types.AttributeValue{":s": dynago.StringValue("x")}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.title, func(t *testing.T) {
+			tc.input.TableName = aws.String("test")
+			tc.input.Key = key
+			_, err := db.UpdateItem(ctx, tc.input)
+			var apiErr smithy.APIError
+			if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+				t.Errorf("expected validation error; got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// readRequest holds the parts shared by Query and Scan requests
+type readRequest struct {
+	view       *view
+	filter     condition
+	projection []path
+	selection  types.Select
+	limit      int
+	start      item
+	consistent *bool
+}
+
+// page is the result of evaluating a read request
+type page struct {
+	items     []item
+	count     int
+	scanned   int
+	size      int
+	lastKey   item
+	countOnly bool
+}
+
+// read evaluates items in order until the limit or the page size is reached
+func (r *readRequest) read(items []item) page {
+	var p page
+	p.countOnly = r.selection == types.SelectCount
+	for i, in := range items {
+		p.scanned++
+		p.size += itemSize(in)
+		projected := r.view.project(in)
+		ok := true
+		if r.filter != nil {
+			// conditions are validated when parsed, evaluation errors are treated as a mismatch
+			ok, _ = r.filter.test(projected)
+		}
+		if ok {
+			p.count++
+			if !p.countOnly {
+				p.items = append(p.items, project(projected, r.projection))
+			}
+		}
+		// like DynamoDB, reaching the limit returns a key even when no items remain
+		last := i == len(items)-1
+		if r.limit > 0 && p.scanned >= r.limit || !last && p.size >= MaxPageSize {
+			p.lastKey = copyItem(r.view.table.keyOf(in, r.view.index))
+			break
+		}
+	}
+	return p
+}
+
+func (db *DB) prepareRead(tableName, indexName *string, filter, projection *string, selection types.Select, limit *int32, start item, consistent *bool, p *parser) (*readRequest, error) {
+	t, err := db.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	v, err := t.view(indexName)
+	if err != nil {
+		return nil, err
+	}
+	if consistent != nil && *consistent && v.index != nil && v.index.global {
+		return nil, validationError("Consistent reads are not supported on global secondary indexes")
+	}
+	r := &readRequest{view: v, selection: selection, start: start, consistent: consistent}
+	if limit != nil {
+		if *limit < 1 {
+			return nil, validationError("1 validation error detected: Value at 'limit' failed to satisfy constraint: Member must have value greater than or equal to 1")
+		}
+		r.limit = int(*limit)
+	}
+	if start != nil {
+		if err := v.validateStart(start); err != nil {
+			return nil, err
+		}
+	}
+	if filter != nil {
+		if r.filter, err = p.parseCondition("FilterExpression", *filter); err != nil {
+			return nil, validationError(err.Error())
+		}
+	}
+	if projection != nil {
+		if r.projection, err = p.parseProjection(*projection); err != nil {
+			return nil, validationError(err.Error())
+		}
+	}
+	switch selection {
+	case "", types.SelectAllAttributes, types.SelectAllProjectedAttributes, types.SelectCount:
+		if projection != nil && selection != "" {
+			return nil, validationError("Cannot specify the ProjectionExpression when choosing to get " + string(selection))
+		}
+	case types.SelectSpecificAttributes:
+		if projection == nil {
+			return nil, validationError("ProjectionExpression must be specified when choosing to get SPECIFIC_ATTRIBUTES")
+		}
+	default:
+		return nil, validationError(fmt.Sprintf("Invalid Select %s", selection))
+	}
+	return r, nil
+}
+
+// Query implements dynago.DynamoDBAPI
+func (db *DB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("Query", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.query(params)
+	return out, operationError("Query", err)
+}
+
+func (db *DB) query(params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	p := newParser(params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	r, err := db.prepareRead(params.TableName, params.IndexName, params.FilterExpression, params.ProjectionExpression, params.Select,
+		params.Limit, params.ExclusiveStartKey, params.ConsistentRead, p)
+	if err != nil {
+		return nil, err
+	}
+	if params.KeyConditionExpression == nil {
+		return nil, validationError("Either the KeyConditions or KeyConditionExpression parameter must be provided in the request.")
+	}
+	cond, err := p.parseCondition("KeyConditionExpression", *params.KeyConditionExpression)
+	if err != nil {
+		return nil, validationError(err.Error())
+	}
+	hash, rng, err := splitKeyCondition(cond, r.view.key)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkUnused(); err != nil {
+		return nil, validationError(err.Error())
+	}
+	if r.filter != nil {
+		for _, name := range r.view.key.names() {
+			if referencesAttribute(r.filter, name) {
+				return nil, validationError("Filter Expression can only contain non-primary key attributes: Primary key attribute: " + name)
+			}
+		}
+	}
+
+	var items []item
+	for _, in := range r.view.items {
+		if !equalValues(in[r.view.key.hash], hash) {
+			continue
+		}
+		if rng != nil {
+			if ok, err := rng.test(in); err != nil {
+				return nil, validationError(err.Error())
+			} else if !ok {
+				continue
+			}
+		}
+		items = append(items, in)
+	}
+	forward := params.ScanIndexForward == nil || *params.ScanIndexForward
+	if !forward {
+		slices.Reverse(items)
+	}
+	if r.start != nil {
+		if !equalValues(r.start[r.view.key.hash], hash) {
+			return nil, validationError("The provided starting key is invalid: The provided key element does not match the partition key of the query")
+		}
+		pos := len(items)
+		for i, in := range items {
+			cmp := r.view.compare(in, r.start)
+			if forward && cmp > 0 || !forward && cmp < 0 {
+				pos = i
+				break
+			}
+		}
+		items = items[pos:]
+	}
+
+	result := r.read(items)
+	out := &dynamodb.QueryOutput{
+		Count:            int32(result.count),
+		ScannedCount:     int32(result.scanned),
+		LastEvaluatedKey: result.lastKey,
+		ConsumedCapacity: readCapacity(params.ReturnConsumedCapacity, r.view.table.name, result.size, params.ConsistentRead),
+	}
+	if !result.countOnly {
+		out.Items = result.items
+		if out.Items == nil {
+			out.Items = []map[string]types.AttributeValue{}
+		}
+	}
+	return out, nil
+}
+
+// splitKeyCondition checks that a key condition has an equality condition on the partition key and
+// an optional condition on the sort key, returns the partition key value and the sort key condition
+func splitKeyCondition(cond condition, key keySchema) (types.AttributeValue, condition, error) {
+	var parts []condition
+	var collect func(c condition)
+	collect = func(c condition) {
+		if and, ok := c.(andCondition); ok {
+			collect(and.left)
+			collect(and.right)
+			return
+		}
+		parts = append(parts, c)
+	}
+	collect(cond)
+
+	unsupported := validationError("Query key condition not supported")
+	if len(parts) > 2 {
+		return nil, nil, validationError("Conditions can be of length 1 or 2 only")
+	}
+	var hash types.AttributeValue
+	var rng condition
+	for _, part := range parts {
+		name, value, err := keyConditionAttribute(part)
+		if err != nil {
+			return nil, nil, unsupported
+		}
+		switch {
+		case name == key.hash && hash == nil:
+			c, ok := part.(compareCondition)
+			if !ok || c.op != "=" {
+				return nil, nil, unsupported
+			}
+			hash = value
+		case name == key.rng && key.rng != "" && rng == nil:
+			rng = part
+		default:
+			return nil, nil, validationError("Query condition missed key schema element: " + key.hash)
+		}
+		if value != nil && typeOf(value) != string(key.attributeType(name)) {
+			return nil, nil, validationError("One or more parameter values were invalid: Condition parameter type does not match schema type")
+		}
+	}
+	if hash == nil {
+		return nil, nil, validationError("Query condition missed key schema element: " + key.hash)
+	}
+	return hash, rng, nil
+}
+
+// keyConditionAttribute returns the key attribute and value referenced by a single key condition
+func keyConditionAttribute(c condition) (string, types.AttributeValue, error) {
+	attribute := func(o operand) (string, bool) {
+		p, ok := o.(pathOperand)
+		if !ok || len(p.path) != 1 || p.path[0].list {
+			return "", false
+		}
+		return p.path[0].name, true
+	}
+	value := func(o operand) (types.AttributeValue, bool) {
+		v, ok := o.(valueOperand)
+		return v.value, ok
+	}
+
+	switch x := c.(type) {
+	case compareCondition:
+		if x.op == "<>" {
+			break
+		}
+		name, ok := attribute(x.left)
+		v, vok := value(x.right)
+		if ok && vok {
+			return name, v, nil
+		}
+	case betweenCondition:
+		name, ok := attribute(x.operand)
+		low, lok := value(x.low)
+		_, hok := value(x.high)
+		if ok && lok && hok {
+			return name, low, nil
+		}
+	case functionCondition:
+		if x.name != "begins_with" || len(x.path) != 1 || x.path[0].list {
+			break
+		}
+		v, ok := value(x.arg)
+		if ok {
+			return x.path[0].name, v, nil
+		}
+	}
+	return "", nil, fmt.Errorf("unsupported key condition")
+}
+
+// referencesAttribute reports whether a condition refers to the top level attribute name
+func referencesAttribute(c condition, name string) bool {
+	refers := func(o operand) bool {
+		switch x := o.(type) {
+		case pathOperand:
+			return x.path[0].name == name
+		case sizeOperand:
+			return x.path[0].name == name
+		}
+		return false
+	}
+	switch x := c.(type) {
+	case andCondition:
+		return referencesAttribute(x.left, name) || referencesAttribute(x.right, name)
+	case orCondition:
+		return referencesAttribute(x.left, name) || referencesAttribute(x.right, name)
+	case notCondition:
+		return referencesAttribute(x.cond, name)
+	case compareCondition:
+		return refers(x.left) || refers(x.right)
+	case betweenCondition:
+		return refers(x.operand) || refers(x.low) || refers(x.high)
+	case inCondition:
+		if refers(x.operand) {
+			return true
+		}
+		for _, o := range x.list {
+			if refers(o) {
+				return true
+			}
+		}
+	case functionCondition:
+		return x.path[0].name == name || x.arg != nil && refers(x.arg)
+	}
+	return false
+}
+
+// Scan implements dynago.DynamoDBAPI. Items are assigned to parallel scan segments by partition key
+func (db *DB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, operationError("Scan", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	out, err := db.scan(params)
+	return out, operationError("Scan", err)
+}
+
+func (db *DB) scan(params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	p := newParser(params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	r, err := db.prepareRead(params.TableName, params.IndexName, params.FilterExpression, params.ProjectionExpression, params.Select,
+		params.Limit, params.ExclusiveStartKey, params.ConsistentRead, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkUnused(); err != nil {
+		return nil, validationError(err.Error())
+	}
+
+	segment, total := aws.ToInt32(params.Segment), aws.ToInt32(params.TotalSegments)
+	if (params.Segment == nil) != (params.TotalSegments == nil) {
+		return nil, validationError("The TotalSegments parameter is required but was not present in the request when Segment parameter is present")
+	}
+	if params.TotalSegments != nil && (total < 1 || total > 1000000 || segment < 0 || segment >= total) {
+		return nil, validationError(fmt.Sprintf("The Segment parameter is zero-based and must be less than parameter TotalSegments: Segment: %d is out of bounds for TotalSegments: %d", segment, total))
+	}
+
+	var items []item
+	for _, in := range r.view.items {
+		if total > 0 && hashOf(in[r.view.key.hash])%uint32(total) != uint32(segment) {
+			continue
+		}
+		if r.start != nil && r.view.compare(in, r.start) <= 0 {
+			continue
+		}
+		items = append(items, in)
+	}
+
+	result := r.read(items)
+	out := &dynamodb.ScanOutput{
+		Count:            int32(result.count),
+		ScannedCount:     int32(result.scanned),
+		LastEvaluatedKey: result.lastKey,
+		ConsumedCapacity: readCapacity(params.ReturnConsumedCapacity, r.view.table.name, result.size, params.ConsistentRead),
+	}
+	if !result.countOnly {
+		out.Items = result.items
+		if out.Items == nil {
+			out.Items = []map[string]types.AttributeValue{}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,285 @@
+package memdb
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// keySchema describes the partition key and optional sort key of a table or index
+type keySchema struct {
+	hash, rng         string
+	hashType, rngType types.ScalarAttributeType
+}
+
+func (k keySchema) names() []string {
+	if k.rng == "" {
+		return []string{k.hash}
+	}
+	return []string{k.hash, k.rng}
+}
+
+func (k keySchema) attributeType(name string) types.ScalarAttributeType {
+	if name == k.hash {
+		return k.hashType
+	}
+	return k.rngType
+}
+
+type index struct {
+	name       string
+	key        keySchema
+	global     bool
+	projection types.ProjectionType
+	include    []string
+}
+
+type table struct {
+	name    string
+	key     keySchema
+	indexes map[string]*index
+	items   map[string]item
+}
+
+func newTable(input *tableDefinition) *table {
+	return &table{
+		name:    input.name,
+		key:     input.key,
+		indexes: input.indexes,
+		items:   map[string]item{},
+	}
+}
+
+// tableDefinition is the validated form of a CreateTableInput
+type tableDefinition struct {
+	name    string
+	key     keySchema
+	indexes map[string]*index
+}
+
+func parseKeySchema(elements []types.KeySchemaElement, attrs map[string]types.ScalarAttributeType) (keySchema, error) {
+	var k keySchema
+	for _, e := range elements {
+		if e.AttributeName == nil {
+			return k, validationError("KeySchema AttributeName must not be empty")
+		}
+		name := *e.AttributeName
+		t, ok := attrs[name]
+		if !ok {
+			return k, validationError(fmt.Sprintf("One or more parameter values were invalid: Some index key attributes are not defined in AttributeDefinitions. Keys: [%s]", name))
+		}
+		switch e.KeyType {
+		case types.KeyTypeHash:
+			k.hash, k.hashType = name, t
+		case types.KeyTypeRange:
+			k.rng, k.rngType = name, t
+		default:
+			return k, validationError(fmt.Sprintf("Invalid KeyType %s", e.KeyType))
+		}
+	}
+	if k.hash == "" {
+		return k, validationError("One or more parameter values were invalid: Missing the hash key")
+	}
+	return k, nil
+}
+
+func parseProjection(p *types.Projection) (types.ProjectionType, []string) {
+	if p == nil || p.ProjectionType == "" {
+		return types.ProjectionTypeAll, nil
+	}
+	return p.ProjectionType, p.NonKeyAttributes
+}
+
+// validateKey checks that key holds exactly the key attributes of the schema with the declared types
+func (k keySchema) validateKey(key item) error {
+	if len(key) != len(k.names()) {
+		return validationError("The provided key element does not match the schema")
+	}
+	for _, name := range k.names() {
+		if err := k.validateAttribute(key, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateItem checks that in holds the key attributes of the schema with the declared types
+func (k keySchema) validateItem(in item) error {
+	for _, name := range k.names() {
+		if _, ok := in[name]; !ok {
+			return validationError(fmt.Sprintf("One or more parameter values were invalid: Missing the key %s in the item", name))
+		}
+		if err := k.validateAttribute(in, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k keySchema) validateAttribute(in item, name string) error {
+	v, ok := in[name]
+	if !ok {
+		return validationError("The provided key element does not match the schema")
+	}
+	if typeOf(v) != string(k.attributeType(name)) {
+		return validationError(fmt.Sprintf("One or more parameter values were invalid: Type mismatch for key %s expected: %s actual: %s", name, k.attributeType(name), typeOf(v)))
+	}
+	switch x := v.(type) {
+	case *types.AttributeValueMemberS:
+		if x.Value == "" {
+			return validationError(fmt.Sprintf("One or more parameter values are not valid. The AttributeValue for a key attribute cannot contain an empty string value. Key: %s", name))
+		}
+	case *types.AttributeValueMemberB:
+		if len(x.Value) == 0 {
+			return validationError(fmt.Sprintf("One or more parameter values are not valid. The AttributeValue for a key attribute cannot contain an empty binary value. Key: %s", name))
+		}
+	case *types.AttributeValueMemberN:
+		if _, ok := parseNumber(x.Value); !ok {
+			return validationError(fmt.Sprintf("The parameter cannot be converted to a numeric value: %s", x.Value))
+		}
+	}
+	return nil
+}
+
+// indexable reports whether in has valid values for every key attribute of the schema.
+// Items without index keys are not part of sparse secondary indexes
+func (k keySchema) indexable(in item) bool {
+	for _, name := range k.names() {
+		v, ok := in[name]
+		if !ok || typeOf(v) != string(k.attributeType(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *table) get(key item) (item, bool) {
+	in, ok := t.items[keyString(key, t.key.names()...)]
+	return in, ok
+}
+
+func (t *table) put(in item) {
+	t.items[keyString(in, t.key.names()...)] = in
+}
+
+func (t *table) delete(key item) {
+	delete(t.items, keyString(key, t.key.names()...))
+}
+
+// keyOf returns the key attributes of in for the table and, when reading an index, the index
+func (t *table) keyOf(in item, idx *index) item {
+	key := item{}
+	for _, name := range t.key.names() {
+		key[name] = in[name]
+	}
+	if idx != nil {
+		for _, name := range idx.key.names() {
+			key[name] = in[name]
+		}
+	}
+	return key
+}
+
+// view is the ordered set of items read by a Query or Scan on the table or one of its indexes
+type view struct {
+	table *table
+	index *index
+	key   keySchema
+	items []item
+}
+
+func (t *table) view(indexName *string) (*view, error) {
+	v := &view{table: t, key: t.key}
+	if indexName != nil {
+		idx, ok := t.indexes[*indexName]
+		if !ok {
+			return nil, validationError(fmt.Sprintf("The table does not have the specified index: %s", *indexName))
+		}
+		v.index, v.key = idx, idx.key
+	}
+	for _, in := range t.items {
+		if v.index != nil && !v.key.indexable(in) {
+			continue
+		}
+		v.items = append(v.items, in)
+	}
+	sort.Slice(v.items, func(i, j int) bool {
+		return v.compare(v.items[i], v.items[j]) < 0
+	})
+	return v, nil
+}
+
+// compare orders items by partition key hash, partition key and then sort key.
+// Items of an index with equal index keys are ordered by the table keys
+func (v *view) compare(a, b item) int {
+	if ha, hb := hashOf(a[v.key.hash]), hashOf(b[v.key.hash]); ha != hb {
+		if ha < hb {
+			return -1
+		}
+		return 1
+	}
+	return compareTuples(v.sortKey(a), v.sortKey(b))
+}
+
+// sortKey returns the values that order items within the view
+func (v *view) sortKey(in item) []types.AttributeValue {
+	values := []types.AttributeValue{in[v.key.hash]}
+	if v.key.rng != "" {
+		values = append(values, in[v.key.rng])
+	}
+	if v.index != nil {
+		for _, name := range v.table.key.names() {
+			values = append(values, in[name])
+		}
+	}
+	return values
+}
+
+// project applies the index projection to in
+func (v *view) project(in item) item {
+	if v.index == nil || v.index.projection == types.ProjectionTypeAll {
+		return in
+	}
+	out := item{}
+	for name, value := range v.table.keyOf(in, v.index) {
+		out[name] = value
+	}
+	if v.index.projection == types.ProjectionTypeInclude {
+		for _, name := range v.index.include {
+			if value, ok := in[name]; ok {
+				out[name] = value
+			}
+		}
+	}
+	return out
+}
+
+// validateStart checks that the exclusive start key holds the key attributes of the table and index
+func (v *view) validateStart(start item) error {
+	names := v.table.key.names()
+	if v.index != nil {
+		for _, name := range v.index.key.names() {
+			if !containsElement(names, name, strings.Compare) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(start) != len(names) {
+		return validationError("The provided starting key is invalid: The provided key element does not match the schema")
+	}
+	for _, name := range names {
+		if _, ok := start[name]; !ok {
+			return validationError("The provided starting key is invalid: The provided key element does not match the schema")
+		}
+	}
+	return nil
+}
+
+func hashOf(v types.AttributeValue) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(keyString(item{"": v}, "")))
+	return h.Sum32()
+}
@@ -0,0 +1,266 @@
+package memdb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+type item = map[string]types.AttributeValue
+
+// typeOf returns the DynamoDB type descriptor of v, eg: S, N, BOOL or SS
+func typeOf(v types.AttributeValue) string {
+	switch v.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberM:
+		return "M"
+	}
+	return ""
+}
+
+func parseNumber(s string) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strings.TrimSpace(s))
+}
+
+// formatNumber formats r the way DynamoDB normalises numbers, without trailing zeros
+func formatNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	s := r.FloatString(38)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func compareNumbers(a, b string) int {
+	x, okx := parseNumber(a)
+	y, oky := parseNumber(b)
+	if !okx || !oky {
+		return strings.Compare(a, b)
+	}
+	return x.Cmp(y)
+}
+
+// compareValues orders two scalar values of the same type, S and B by bytes and N numerically.
+// ok is false when the values can not be ordered
+func compareValues(a, b types.AttributeValue) (cmp int, ok bool) {
+	switch x := a.(type) {
+	case *types.AttributeValueMemberS:
+		if y, ok := b.(*types.AttributeValueMemberS); ok {
+			return strings.Compare(x.Value, y.Value), true
+		}
+	case *types.AttributeValueMemberN:
+		if y, ok := b.(*types.AttributeValueMemberN); ok {
+			return compareNumbers(x.Value, y.Value), true
+		}
+	case *types.AttributeValueMemberB:
+		if y, ok := b.(*types.AttributeValueMemberB); ok {
+			return bytes.Compare(x.Value, y.Value), true
+		}
+	}
+	return 0, false
+}
+
+func compareTuples(a, b []types.AttributeValue) int {
+	for i := range min(len(a), len(b)) {
+		if c, _ := compareValues(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+func equalValues(a, b types.AttributeValue) bool {
+	switch x := a.(type) {
+	case *types.AttributeValueMemberS:
+		y, ok := b.(*types.AttributeValueMemberS)
+		return ok && x.Value == y.Value
+	case *types.AttributeValueMemberN:
+		y, ok := b.(*types.AttributeValueMemberN)
+		return ok && compareNumbers(x.Value, y.Value) == 0
+	case *types.AttributeValueMemberB:
+		y, ok := b.(*types.AttributeValueMemberB)
+		return ok && bytes.Equal(x.Value, y.Value)
+	case *types.AttributeValueMemberBOOL:
+		y, ok := b.(*types.AttributeValueMemberBOOL)
+		return ok && x.Value == y.Value
+	case *types.AttributeValueMemberNULL:
+		_, ok := b.(*types.AttributeValueMemberNULL)
+		return ok
+	case *types.AttributeValueMemberSS:
+		y, ok := b.(*types.AttributeValueMemberSS)
+		return ok && equalSets(x.Value, y.Value, strings.Compare)
+	case *types.AttributeValueMemberNS:
+		y, ok := b.(*types.AttributeValueMemberNS)
+		return ok && equalSets(x.Value, y.Value, compareNumbers)
+	case *types.AttributeValueMemberBS:
+		y, ok := b.(*types.AttributeValueMemberBS)
+		return ok && equalSets(x.Value, y.Value, bytes.Compare)
+	case *types.AttributeValueMemberL:
+		y, ok := b.(*types.AttributeValueMemberL)
+		if !ok || len(x.Value) != len(y.Value) {
+			return false
+		}
+		for i := range x.Value {
+			if !equalValues(x.Value[i], y.Value[i]) {
+				return false
+			}
+		}
+		return true
+	case *types.AttributeValueMemberM:
+		y, ok := b.(*types.AttributeValueMemberM)
+		if !ok || len(x.Value) != len(y.Value) {
+			return false
+		}
+		for k, v := range x.Value {
+			w, ok := y.Value[k]
+			if !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func equalSets[E any](a, b []E, cmp func(E, E) int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, x := range a {
+		if !containsElement(b, x, cmp) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsElement[E any](set []E, e E, cmp func(E, E) int) bool {
+	for _, x := range set {
+		if cmp(x, e) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// copyValue returns a deep copy of v so stored items never share memory with callers
+func copyValue(v types.AttributeValue) types.AttributeValue {
+	switch x := v.(type) {
+	case *types.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: x.Value}
+	case *types.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: x.Value}
+	case *types.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: bytes.Clone(x.Value)}
+	case *types.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: x.Value}
+	case *types.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: x.Value}
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: append([]string(nil), x.Value...)}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: append([]string(nil), x.Value...)}
+	case *types.AttributeValueMemberBS:
+		values := make([][]byte, len(x.Value))
+		for i, b := range x.Value {
+			values[i] = bytes.Clone(b)
+		}
+		return &types.AttributeValueMemberBS{Value: values}
+	case *types.AttributeValueMemberL:
+		values := make([]types.AttributeValue, len(x.Value))
+		for i, e := range x.Value {
+			values[i] = copyValue(e)
+		}
+		return &types.AttributeValueMemberL{Value: values}
+	case *types.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: copyItem(x.Value)}
+	}
+	return v
+}
+
+func copyItem(in item) item {
+	if in == nil {
+		return nil
+	}
+	out := make(item, len(in))
+	for k, v := range in {
+		out[k] = copyValue(v)
+	}
+	return out
+}
+
+// itemSize returns the size of an item in bytes following the DynamoDB item size rules
+func itemSize(in item) int {
+	return dynago.ItemSize(in)
+}
+
+// sizeFunction implements the size() expression function
+func sizeFunction(v types.AttributeValue) (int, bool) {
+	switch x := v.(type) {
+	case *types.AttributeValueMemberS:
+		return utf8.RuneCountInString(x.Value), true
+	case *types.AttributeValueMemberB:
+		return len(x.Value), true
+	case *types.AttributeValueMemberSS:
+		return len(x.Value), true
+	case *types.AttributeValueMemberNS:
+		return len(x.Value), true
+	case *types.AttributeValueMemberBS:
+		return len(x.Value), true
+	case *types.AttributeValueMemberL:
+		return len(x.Value), true
+	case *types.AttributeValueMemberM:
+		return len(x.Value), true
+	}
+	return 0, false
+}
+
+// keyString returns a stable string for the key attributes of an item
+func keyString(in item, names ...string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	var b strings.Builder
+	for _, name := range sorted {
+		b.WriteString(name)
+		b.WriteByte('=')
+		switch v := in[name].(type) {
+		case *types.AttributeValueMemberS:
+			b.WriteString("S:" + v.Value)
+		case *types.AttributeValueMemberN:
+			r, ok := parseNumber(v.Value)
+			if ok {
+				b.WriteString("N:" + formatNumber(r))
+			} else {
+				b.WriteString("N:" + v.Value)
+			}
+		case *types.AttributeValueMemberB:
+			b.WriteString("B:" + base64.StdEncoding.EncodeToString(v.Value))
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package memdb
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type writeKind int
+
+const (
+	writePut writeKind = iota
+	writeUpdate
+	writeDelete
+	writeCheck
+)
+
+// write is a validated put, update, delete or condition check. Writes are prepared before any of them
+// is applied so batches and transactions can be rejected as a whole
+type write struct {
+	kind      writeKind
+	table     *table
+	key       item
+	old       item
+	new       item
+	condition condition
+	update    *updateExpression
+	returnOld types.ReturnValuesOnConditionCheckFailure
+}
+
+func (db *DB) preparePut(tableName *string, in item, cond *string, names map[string]string, values map[string]types.AttributeValue) (*write, error) {
+	t, err := db.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.key.validateItem(in); err != nil {
+		return nil, err
+	}
+	if err := validateIndexKeys(t, in); err != nil {
+		return nil, err
+	}
+	if itemSize(in) > MaxItemSize {
+		return nil, validationError("Item size has exceeded the maximum allowed size")
+	}
+	w := &write{kind: writePut, table: t, key: t.keyOf(in, nil), new: copyItem(in)}
+	if err := w.parse(nil, cond, names, values); err != nil {
+		return nil, err
+	}
+	w.old, _ = t.get(w.key)
+	return w, nil
+}
+
+func (db *DB) prepareUpdate(tableName *string, key item, update, cond *string, names map[string]string, values map[string]types.AttributeValue) (*write, error) {
+	w, err := db.prepareKeyWrite(writeUpdate, tableName, key, update, cond, names, values)
+	if err != nil {
+		return nil, err
+	}
+	if w.update != nil {
+		for _, p := range w.update.paths() {
+			if slices.Contains(w.table.key.names(), p[0].name) {
+				return nil, validationError(fmt.Sprintf("One or more parameter values were invalid: Cannot update attribute %s. This attribute is part of the key", p[0].name))
+			}
+		}
+	}
+	return w, nil
+}
+
+func (db *DB) prepareDelete(tableName *string, key item, cond *string, names map[string]string, values map[string]types.AttributeValue) (*write, error) {
+	return db.prepareKeyWrite(writeDelete, tableName, key, nil, cond, names, values)
+}
+
+func (db *DB) prepareCheck(tableName *string, key item, cond *string, names map[string]string, values map[string]types.AttributeValue) (*write, error) {
+	return db.prepareKeyWrite(writeCheck, tableName, key, nil, cond, names, values)
+}
+
+func (db *DB) prepareKeyWrite(kind writeKind, tableName *string, key item, update, cond *string, names map[string]string, values map[string]types.AttributeValue) (*write, error) {
+	t, err := db.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.key.validateKey(key); err != nil {
+		return nil, err
+	}
+	w := &write{kind: kind, table: t, key: copyItem(key)}
+	if err := w.parse(update, cond, names, values); err != nil {
+		return nil, err
+	}
+	w.old, _ = t.get(key)
+	return w, nil
+}
+
+func (w *write) parse(update, cond *string, names map[string]string, values map[string]types.AttributeValue) error {
+	p := newParser(names, values)
+	var err error
+	if update != nil {
+		if w.update, err = p.parseUpdate(*update); err != nil {
+			return validationError(err.Error())
+		}
+	}
+	if cond != nil {
+		if w.condition, err = p.parseCondition("ConditionExpression", *cond); err != nil {
+			return validationError(err.Error())
+		}
+	}
+	if err := p.checkUnused(); err != nil {
+		return validationError(err.Error())
+	}
+	return nil
+}
+
+// check evaluates the condition against the current item and computes the result of updates.
+// Returns a *types.ConditionalCheckFailedException when the condition is not met
+func (w *write) check(returnOld types.ReturnValuesOnConditionCheckFailure) error {
+	current := w.old
+	if current == nil {
+		current = item{}
+	}
+	if w.condition != nil {
+		ok, err := w.condition.test(current)
+		if err != nil {
+			return validationError(err.Error())
+		}
+		if !ok {
+			return conditionFailed(w.old, returnOld == types.ReturnValuesOnConditionCheckFailureAllOld)
+		}
+	}
+
+	if w.kind == writeUpdate {
+		base := w.old
+		if base == nil {
+			base = copyItem(w.key)
+		}
+		if w.update == nil {
+			w.new = copyItem(base)
+			return nil
+		}
+		updated, err := applyUpdate(base, w.update)
+		if err != nil {
+			return validationError(err.Error())
+		}
+		if err := validateIndexKeys(w.table, updated); err != nil {
+			return err
+		}
+		if itemSize(updated) > MaxItemSize {
+			return validationError("Item size to update has exceeded the maximum allowed size")
+		}
+		w.new = updated
+	}
+	return nil
+}
+
+// isConditionFailed reports whether err was returned by write.check because the condition was not met
+func isConditionFailed(err error) bool {
+	var ccf *types.ConditionalCheckFailedException
+	return errors.As(err, &ccf)
+}
+
+func (w *write) apply() {
+	switch w.kind {
+	case writePut, writeUpdate:
+		w.table.put(w.new)
+	case writeDelete:
+		if w.old != nil {
+			w.table.delete(w.key)
+		}
+	}
+}
+
+// size returns the size used to compute consumed write capacity, the larger of the old and new item
+func (w *write) size() int {
+	return max(itemSize(w.old), itemSize(w.new))
+}
+
+// updated returns the top level attributes of source that were modified by the update expression
+func (w *write) updated(source item) item {
+	if w.update == nil || source == nil {
+		return nil
+	}
+	out := item{}
+	for _, p := range w.update.paths() {
+		if v, ok := source[p[0].name]; ok {
+			out[p[0].name] = copyValue(v)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// validateIndexKeys checks that index key attributes present in the item have the type declared for the index
+func validateIndexKeys(t *table, in item) error {
+	for _, name := range sortedKeys(t.indexes) {
+		idx := t.indexes[name]
+		for _, attr := range idx.key.names() {
+			v, ok := in[attr]
+			if !ok {
+				continue
+			}
+			if typeOf(v) != string(idx.key.attributeType(attr)) {
+				return validationError(fmt.Sprintf("One or more parameter values were invalid: Type mismatch for Index Key %s Expected: %s Actual: %s IndexName: %s", attr, idx.key.attributeType(attr), typeOf(v), name))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/oolio-group/dynago"
+)
+
+func TestBatchWriteItems(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	// more than one chunk of items
+	items := make([]dynago.AttributeRecord, 0, 60)
+	keys := make([]dynago.AttributeRecord, 0, 60)
+	for idx := range 60 {
+		user := User{Id: fmt.Sprintf("%d", idx), Pk: "users#batch", Sk: fmt.Sprintf("user#%02d", idx)}
+		item, err := attributevalue.MarshalMap(user)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		key := table.NewKeys(dynago.StringValue(user.Pk), dynago.StringValue(user.Sk))
+		for k, v := range key {
+			item[k] = v
+		}
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+
+	err := table.BatchWriteItems(ctx, items)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var out []User
+	err = table.BatchGetItems(ctx, keys, &out)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(out) != 60 {
+		t.Errorf("expected 60 items to be written; got %d", len(out))
+	}
+
+	err = table.BatchDeleteItems(ctx, keys)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	out = nil
+	err = table.BatchGetItems(ctx, keys, &out)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(out) != 0 {
+		t.Errorf("expected all items to be deleted; got %d", len(out))
+	}
+}
+
+func TestBatchWriteItemsError(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	valid := table.NewKeys(dynago.StringValue("users#batch"), dynago.StringValue("user#1"))
+	invalid := table.NewKeys(dynago.StringValue(""), dynago.StringValue("user#2"))
+
+	err := table.BatchWriteItems(ctx, []dynago.AttributeRecord{valid, invalid})
+	var batchErr *dynago.BatchWriteError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchWriteError; got %v", err)
+	}
+	if len(batchErr.Puts) != 2 || len(batchErr.Deletes) != 0 {
+		t.Errorf("expected the failed chunk to be reported; got %v", batchErr)
+	}
+	if batchErr.Err == nil {
+		t.Error("expected request error to be wrapped")
+	}
+
+	err = table.BatchDeleteItems(ctx, []dynago.AttributeRecord{invalid})
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchWriteError; got %v", err)
+	}
+	if len(batchErr.Deletes) != 1 {
+		t.Errorf("expected the failed key to be reported; got %v", batchErr)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+func TestBatchWriter(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("users#writer")
+
+	// existing items that will be deleted by the writer
+	for idx := range 10 {
+		sk := fmt.Sprintf("old#%d", idx)
+		if err := table.PutItem(ctx, pk, dynago.StringValue(sk), User{Id: sk}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	w := table.NewBatchWriter(ctx, dynago.BatchWriterOptions{Parallelism: 3})
+	for idx := range 80 {
+		sk := fmt.Sprintf("user#%02d", idx)
+		if err := w.Put(pk, dynago.StringValue(sk), User{Id: sk, Pk: "users#writer", Sk: sk}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	for idx := range 10 {
+		if err := w.Delete(pk, dynago.StringValue(fmt.Sprintf("old#%d", idx))); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	// the last chunk is still pending and already holds a request for old#9
+	err := w.Put(pk, dynago.StringValue("old#9"), User{})
+	if !errors.Is(err, dynago.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error; got %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := w.Put(pk, dynago.StringValue("late"), User{}); err == nil {
+		t.Error("expected put on closed writer to fail")
+	}
+
+	var out []User
+	_, err = table.Query(ctx, "pk = :pk", map[string]dynago.Attribute{":pk": pk}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(out) != 80 {
+		t.Errorf("expected 80 items after writes and deletes; got %d", len(out))
+	}
+	for _, user := range out {
+		if user.Pk != "users#writer" {
+			t.Errorf("expected deleted items to be removed; got %v", user)
+		}
+	}
+}
+
+func TestBatchWriterFlushError(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	w := table.NewBatchWriter(ctx, dynago.BatchWriterOptions{})
+	if err := w.Put(dynago.StringValue(""), dynago.StringValue("invalid"), User{}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	err := w.Flush()
+	var batchErr *dynago.BatchWriteError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchWriteError; got %v", err)
+	}
+	if len(batchErr.Puts) != 1 {
+		t.Errorf("expected failed put to be reported; got %v", batchErr)
+	}
+	// failures are reported once
+	if err := w.Close(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
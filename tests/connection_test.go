@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/pagination"
+)
+
+func TestPaginatorConnection(t *testing.T) {
+	client := prepareTable(t)
+	ctx := context.TODO()
+
+	items := make([]*dynago.TransactPutItemsInput, 0, 12)
+	for idx := range 12 {
+		user := User{
+			Id:   fmt.Sprintf("%d", idx),
+			City: []string{"Melbourne", "Melbourne", "Perth"}[idx%3],
+			Pk:   "users#conn",
+			Sk:   fmt.Sprintf("user#%02d", idx),
+		}
+		items = append(items, &dynago.TransactPutItemsInput{
+			PartitionKeyValue: dynago.StringValue(user.Pk),
+			SortKeyValue:      dynago.StringValue(user.Sk),
+			Item:              user,
+		})
+	}
+	if err := client.TransactPutItems(ctx, items); err != nil {
+		t.Fatalf("prepare table failed; got %s", err)
+	}
+	values := map[string]dynago.Attribute{":pk": dynago.StringValue("users#conn")}
+	// Melbourne users are 0, 1, 3, 4, 6, 7, 9 and 10
+	filter := dynago.WithFilter("City = :city")
+	filterValues := map[string]dynago.Attribute{
+		":pk":   dynago.StringValue("users#conn"),
+		":city": dynago.StringValue("Melbourne"),
+	}
+	users := pagination.NewPaginator(dynago.NewTable[User](client))
+
+	ids := func(conn *pagination.Connection[User]) []string {
+		out := make([]string, len(conn.Edges))
+		for i, edge := range conn.Edges {
+			out[i] = edge.Node.Id
+		}
+		return out
+	}
+	page := func(t *testing.T, values map[string]dynago.Attribute, args pagination.ConnectionArgs, opts ...dynago.QueryOptions) *pagination.Connection[User] {
+		t.Helper()
+		conn, err := users.Query(ctx, "pk = :pk", values, args, opts...)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		return conn
+	}
+	expectPage := func(t *testing.T, conn *pagination.Connection[User], expected []string, hasPrevious, hasNext bool) {
+		t.Helper()
+		if got := ids(conn); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected users %v; got %v", expected, got)
+		}
+		if conn.PageInfo.HasPreviousPage != hasPrevious || conn.PageInfo.HasNextPage != hasNext {
+			t.Errorf("expected previous %v and next %v; got %+v", hasPrevious, hasNext, conn.PageInfo)
+		}
+	}
+
+	t.Run("forward and backward", func(t *testing.T) {
+		first := page(t, filterValues, pagination.ConnectionArgs{First: 3}, filter)
+		expectPage(t, first, []string{"0", "1", "3"}, false, true)
+		second := page(t, filterValues, pagination.ConnectionArgs{First: 3, After: first.PageInfo.EndCursor}, filter)
+		expectPage(t, second, []string{"4", "6", "7"}, true, true)
+		third := page(t, filterValues, pagination.ConnectionArgs{First: 3, After: second.PageInfo.EndCursor}, filter)
+		expectPage(t, third, []string{"9", "10"}, true, false)
+
+		prev := page(t, filterValues, pagination.ConnectionArgs{Last: 3, Before: third.PageInfo.StartCursor}, filter)
+		expectPage(t, prev, []string{"4", "6", "7"}, true, true)
+		prev = page(t, filterValues, pagination.ConnectionArgs{Last: 3, Before: prev.PageInfo.StartCursor}, filter)
+		expectPage(t, prev, []string{"0", "1", "3"}, false, true)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		last := page(t, filterValues, pagination.ConnectionArgs{Last: 3}, filter)
+		expectPage(t, last, []string{"7", "9", "10"}, true, false)
+	})
+
+	t.Run("edge cursors", func(t *testing.T) {
+		first := page(t, values, pagination.ConnectionArgs{First: 4})
+		next := page(t, values, pagination.ConnectionArgs{First: 2, After: first.Edges[1].Cursor})
+		expectPage(t, next, []string{"2", "3"}, true, true)
+	})
+
+	t.Run("descending order", func(t *testing.T) {
+		desc := dynago.SortByAsc(false)
+		first := page(t, values, pagination.ConnectionArgs{First: 4}, desc)
+		expectPage(t, first, []string{"11", "10", "9", "8"}, false, true)
+		second := page(t, values, pagination.ConnectionArgs{First: 4, After: first.PageInfo.EndCursor}, desc)
+		prev := page(t, values, pagination.ConnectionArgs{Last: 4, Before: second.PageInfo.StartCursor}, desc)
+		expectPage(t, prev, []string{"11", "10", "9", "8"}, false, true)
+	})
+
+	t.Run("cursor of another query", func(t *testing.T) {
+		first := page(t, filterValues, pagination.ConnectionArgs{First: 2}, filter)
+		_, err := users.Query(ctx, "pk = :pk", values, pagination.ConnectionArgs{First: 2, After: first.PageInfo.EndCursor})
+		if !errors.Is(err, pagination.ErrCursorQueryMismatch) {
+			t.Errorf("expected ErrCursorQueryMismatch; got %v", err)
+		}
+	})
+
+	t.Run("page size is required", func(t *testing.T) {
+		_, err := users.Query(ctx, "pk = :pk", values, pagination.ConnectionArgs{First: 2, Last: 2})
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+type Order struct {
+	Sku   string
+	Units int
+}
+
+type InventoryItem struct {
+	Sku   string
+	Units int
+}
+
+func TestCrossTableTx(t *testing.T) {
+	orders := prepareTable(t)
+	ctx := context.TODO()
+
+	name := getRandomTableName(t)
+	if err := testdb.CreateTable(ctx, name, "Sku", ""); err != nil {
+		t.Fatalf("expected table creation to succeed, got %s", err)
+	}
+	inventory := orders.ForTable(dynago.ClientOptions{TableName: name, PartitionKeyName: "Sku"})
+
+	sku := dynago.StringValue("widget")
+	if err := inventory.PutItem(ctx, sku, nil, InventoryItem{Sku: "widget", Units: 1}); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	order := func(id string) *dynago.Tx {
+		tx := orders.NewTx().Put(dynago.StringValue("order#"+id), dynago.StringValue("order#"+id), Order{Sku: "widget", Units: 1})
+		tx.On(inventory).Update(sku, nil, dynago.NewUpdate().Add("Units", -1),
+			dynago.WithTxCondition("Units > :zero", map[string]dynago.Attribute{":zero": dynago.NumberValue(0)}))
+		return tx
+	}
+
+	t.Run("items of both tables are committed", func(t *testing.T) {
+		if err := order("1").Commit(ctx); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var item InventoryItem
+		err, _ := inventory.GetItem(ctx, sku, nil, &item)
+		if err != nil || item.Units != 0 {
+			t.Errorf("expected inventory to be decremented; got %v, %v", item, err)
+		}
+		var out Order
+		err, found := orders.GetItem(ctx, dynago.StringValue("order#1"), dynago.StringValue("order#1"), &out)
+		if err != nil || !found {
+			t.Errorf("expected order to be written; got %v", err)
+		}
+	})
+
+	t.Run("failed condition of one table cancels both", func(t *testing.T) {
+		err := order("2").Commit(ctx)
+		var txErr *dynago.TransactionCanceledError
+		if !errors.As(err, &txErr) || len(txErr.Failed()) != 1 || txErr.Failed()[0].Index != 1 {
+			t.Fatalf("expected inventory item to fail; got %v", err)
+		}
+		var out Order
+		err, found := orders.GetItem(ctx, dynago.StringValue("order#2"), dynago.StringValue("order#2"), &out)
+		if err != nil || found {
+			t.Errorf("expected order not to be written; got %v", err)
+		}
+	})
+
+	t.Run("client with another connection is rejected", func(t *testing.T) {
+		other := dynago.NewClientFromAPI(throttledAPI{}, dynago.ClientOptions{TableName: name, PartitionKeyName: "Sku"})
+		tx := orders.NewTx()
+		tx.On(other).Delete(sku, nil)
+		if err := tx.Commit(ctx); !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+}
+
+func TestForTableSettings(t *testing.T) {
+	ctx := context.TODO()
+	var logs bytes.Buffer
+	api := &cancelingAPI{codes: [][]string{{"TransactionConflict"}}}
+	orders := dynago.NewClientFromAPI(api, dynago.ClientOptions{
+		TableName:              "orders",
+		PartitionKeyName:       "pk",
+		SortKeyName:            "sk",
+		Logger:                 slog.New(slog.NewTextHandler(&logs, nil)),
+		TransactionRetryPolicy: dynago.RetryPolicy{MaxAttempts: 1},
+	})
+	inventory := orders.ForTable(dynago.ClientOptions{TableName: "inventory", PartitionKeyName: "Sku"})
+	sku := dynago.StringValue("widget")
+
+	t.Run("retry policy is inherited", func(t *testing.T) {
+		err := inventory.NewTx().Delete(sku, nil).Commit(ctx)
+		var txErr *dynago.TransactionCanceledError
+		if !errors.As(err, &txErr) || len(api.tokens) != 1 {
+			t.Errorf("expected a single attempt to fail; got %v after %d attempts", err, len(api.tokens))
+		}
+	})
+
+	t.Run("logger is inherited", func(t *testing.T) {
+		inventory.WithUpdateItem(sku, nil, dynago.NewUpdate())
+		if !strings.Contains(logs.String(), "failed to build update expression") {
+			t.Errorf("expected failure to be logged; got %q", logs.String())
+		}
+	})
+
+	t.Run("options override inherited settings", func(t *testing.T) {
+		var own bytes.Buffer
+		other := orders.ForTable(dynago.ClientOptions{
+			TableName:        "inventory",
+			PartitionKeyName: "Sku",
+			Logger:           slog.New(slog.NewTextHandler(&own, nil)),
+		})
+		logs.Reset()
+		other.WithUpdateItem(sku, nil, dynago.NewUpdate())
+		if logs.Len() != 0 || !strings.Contains(own.String(), "failed to build update expression") {
+			t.Errorf("expected failure to be logged by the own logger; got %q and %q", own.String(), logs.String())
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+type Event struct {
+	Name string
+	PK   string
+	TS   int64
+}
+
+// prepareEventsTable creates a table keyed on PK and a numeric TS sort key
+func prepareEventsTable(t *testing.T) *dynago.Client {
+	t.Helper()
+	ctx := context.TODO()
+	name := getRandomTableName(t)
+	table, err := dynago.NewClient(ctx, dynago.ClientOptions{
+		TableName: name,
+		Endpoint: &dynago.EndpointResolver{
+			EndpointURL:     testdb.Endpoint(),
+			AccessKeyID:     "dummy",
+			SecretAccessKey: "dummy",
+		},
+		PartitionKeyName: "PK",
+		SortKeyName:      "TS",
+		Region:           "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("expected configuration to succeed, got %s", err)
+	}
+	err = testdb.CreateTableFromInput(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(name),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("PK"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("TS"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("PK"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("TS"), KeyType: types.KeyTypeRange},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		t.Fatalf("expected table creation to succeed, got %s", err)
+	}
+	return table
+}
+
+func TestDeleteItem(t *testing.T) {
+	table := prepareEventsTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("events#1")
+
+	for ts := int64(1); ts <= 3; ts++ {
+		err := table.PutItem(ctx, pk, dynago.NumberValue(ts), Event{Name: "login"})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	t.Run("delete with configured key names", func(t *testing.T) {
+		_, err := table.DeleteItem(ctx, pk, dynago.NumberValue(1))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var out Event
+		err, found := table.GetItem(ctx, pk, dynago.NumberValue(1), &out)
+		if err != nil || found {
+			t.Errorf("expected item to be deleted; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("return old values", func(t *testing.T) {
+		old, err := table.DeleteItem(ctx, pk, dynago.NumberValue(2), dynago.WithDeleteReturnOldValues())
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		name, ok := old["Name"].(*types.AttributeValueMemberS)
+		if !ok || name.Value != "login" {
+			t.Errorf("expected deleted item attributes; got %v", old)
+		}
+	})
+
+	t.Run("condition failure keeps the item", func(t *testing.T) {
+		_, err := table.DeleteItem(ctx, pk, dynago.NumberValue(3),
+			dynago.WithDeleteCondition("TS = :ts", map[string]dynago.Attribute{":ts": dynago.NumberValue(4)}),
+		)
+		if !errors.Is(err, dynago.ErrConditionFailed) {
+			t.Fatalf("expected ErrConditionFailed; got %v", err)
+		}
+		var out Event
+		err, found := table.GetItem(ctx, pk, dynago.NumberValue(3), &out)
+		if err != nil || !found {
+			t.Errorf("expected item to remain; got %v", err)
+		}
+	})
+}
+
+func TestTransactItemsConfiguredKeys(t *testing.T) {
+	table := prepareEventsTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("events#tx")
+
+	err := table.PutItem(ctx, pk, dynago.NumberValue(1), Event{Name: "login"})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	err = table.TransactItems(ctx,
+		table.WithDeleteItem(pk, dynago.NumberValue(1)),
+		table.WithPutItem(pk, dynago.NumberValue(2), Event{Name: "logout"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	var out []Event
+	_, err = table.Query(ctx, "PK = :pk", map[string]dynago.Attribute{":pk": pk}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(out) != 1 || out[0].Name != "logout" || out[0].TS != 2 {
+		t.Errorf("expected only the new event; got %v", out)
+	}
+
+	err = table.TransactDeleteItems(ctx, []*dynago.TransactDeleteItemsInput{
+		{PartitionKeyValue: pk, SortKeyValue: dynago.NumberValue(2)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var remaining []Event
+	_, err = table.Query(ctx, "PK = :pk", map[string]dynago.Attribute{":pk": pk}, &remaining)
+	if err != nil || len(remaining) != 0 {
+		t.Errorf("expected every event to be deleted; got %v, %v", remaining, err)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+func TestConditionFailedError(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("users#lock")
+
+	err := table.PutItem(ctx, pk, pk, User{Id: "1"}, dynago.WithOptimisticLock("Version", 0))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	// version 0 is stale after the first write
+	err = table.PutItem(ctx, pk, pk, User{Id: "1"}, dynago.WithOptimisticLock("Version", 0))
+	if !errors.Is(err, dynago.ErrConditionFailed) {
+		t.Errorf("expected ErrConditionFailed; got %v", err)
+	}
+	var conditionErr *dynago.ConditionFailedError
+	if !errors.As(err, &conditionErr) {
+		t.Errorf("expected ConditionFailedError; got %v", err)
+	}
+	// SDK error types remain available
+	var ccf *types.ConditionalCheckFailedException
+	if !errors.As(err, &ccf) {
+		t.Errorf("expected wrapped ConditionalCheckFailedException; got %v", err)
+	}
+}
+
+func TestTransactionCanceledError(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	err := table.PutItem(ctx, dynago.StringValue("users#tx"), dynago.StringValue("existing"), User{Id: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	exists := table.WithPutItem(dynago.StringValue("users#tx"), dynago.StringValue("existing"), User{Id: "2"})
+	exists.Put.ConditionExpression = aws.String("attribute_not_exists(pk)")
+	err = table.TransactItems(ctx,
+		table.WithPutItem(dynago.StringValue("users#tx"), dynago.StringValue("new"), User{Id: "3"}),
+		exists,
+	)
+	if !errors.Is(err, dynago.ErrConditionFailed) {
+		t.Errorf("expected ErrConditionFailed; got %v", err)
+	}
+	var txErr *dynago.TransactionCanceledError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("expected TransactionCanceledError; got %v", err)
+	}
+	if len(txErr.Reasons) != 2 {
+		t.Fatalf("expected a reason for each item; got %v", txErr.Reasons)
+	}
+	failed := txErr.Failed()
+	if len(failed) != 1 || failed[0].Index != 1 || failed[0].Code != "ConditionalCheckFailed" {
+		t.Errorf("expected second item to cause the cancellation; got %v", failed)
+	}
+}
+
+func TestValidationAndNotFoundErrors(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	var out []User
+	_, err := table.Query(ctx, "pk = = :pk", map[string]dynago.Attribute{":pk": dynago.StringValue("users")}, &out)
+	if !errors.Is(err, dynago.ErrValidation) {
+		t.Errorf("expected ErrValidation; got %v", err)
+	}
+
+	missing := *table
+	missing.TableName = "missing-" + table.TableName
+	_, err = missing.Query(ctx, "pk = :pk", map[string]dynago.Attribute{":pk": dynago.StringValue("users")}, &out)
+	if !errors.Is(err, dynago.ErrNotFound) {
+		t.Errorf("expected ErrNotFound; got %v", err)
+	}
+}
+
+// throttledAPI rejects every read with a throughput error
+type throttledAPI struct {
+	dynago.DynamoDBAPI
+}
+
+func (throttledAPI) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return nil, &types.ProvisionedThroughputExceededException{Message: aws.String("throughput exceeded")}
+}
+
+func TestThrottledError(t *testing.T) {
+	table := dynago.NewClientFromAPI(throttledAPI{}, dynago.ClientOptions{TableName: "throttled", PartitionKeyName: "pk", SortKeyName: "sk"})
+
+	var out User
+	err, _ := table.GetItem(context.TODO(), dynago.StringValue("a"), dynago.StringValue("b"), &out)
+	if !errors.Is(err, dynago.ErrThrottled) {
+		t.Errorf("expected ErrThrottled; got %v", err)
+	}
+	if errors.Is(err, dynago.ErrConditionFailed) {
+		t.Errorf("expected throttling not to match ErrConditionFailed")
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/expression"
+)
+
+type Shipment struct {
+	Name   string
+	Status string
+	Date   string
+	Tags   []string
+}
+
+func TestExpressions(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("shipments")
+
+	shipments := map[string]Shipment{
+		"shipment#1": {Name: "one", Status: "pending", Date: "2024-01-01", Tags: []string{"fragile"}},
+		"shipment#2": {Name: "two", Status: "shipped", Date: "2024-01-02", Tags: []string{"fragile", "heavy"}},
+		"shipment#3": {Name: "three", Status: "delivered", Date: "2024-01-03"},
+		"other#1":    {Name: "other", Status: "shipped", Date: "2024-01-04"},
+	}
+	for sk, shipment := range shipments {
+		if err := table.PutItem(ctx, pk, dynago.StringValue(sk), shipment); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	key := expression.Key("pk").Equal(pk).And(expression.Key("sk").BeginsWith("shipment#"))
+
+	t.Run("query with key condition, filter and reserved words", func(t *testing.T) {
+		var out []Shipment
+		_, err := table.Query(ctx, "", nil, &out,
+			dynago.WithKeyCondition(key),
+			dynago.WithFilterExpr(expression.Attr("Status").In(dynago.StringValue("shipped"), dynago.StringValue("delivered"))),
+			dynago.WithFields([]string{"Name", "Status"}),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(out) != 2 || out[0].Name != "two" || out[1].Name != "three" || out[0].Date != "" {
+			t.Errorf("expected shipments two and three without dates; got %v", out)
+		}
+	})
+
+	t.Run("expressions combine with raw conditions", func(t *testing.T) {
+		var out []Shipment
+		_, err := table.Query(ctx, "pk = :pk", map[string]dynago.Attribute{":pk": pk}, &out,
+			dynago.WithFilterExpr(expression.Size("Tags").GreaterThan(1)),
+		)
+		if err != nil || len(out) != 1 || out[0].Name != "two" {
+			t.Errorf("expected shipment two; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("scan filter", func(t *testing.T) {
+		var out []Shipment
+		_, err := table.Scan(ctx, &out, dynago.WithScanFilterExpr(
+			expression.Attr("Date").Between(dynago.StringValue("2024-01-02"), dynago.StringValue("2024-01-03")),
+		))
+		if err != nil || len(out) != 2 {
+			t.Errorf("expected 2 shipments; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("put condition", func(t *testing.T) {
+		err := table.PutItem(ctx, pk, dynago.StringValue("shipment#1"), Shipment{Name: "replaced"},
+			dynago.WithPutConditionExpr(expression.AttributeNotExists("pk")))
+		if !errors.Is(err, dynago.ErrConditionFailed) {
+			t.Errorf("expected ErrConditionFailed; got %v", err)
+		}
+	})
+
+	t.Run("update condition", func(t *testing.T) {
+		_, err := table.UpdateItem(ctx, pk, dynago.StringValue("shipment#1"), dynago.NewUpdate().Set("Status", "shipped"),
+			dynago.WithUpdateConditionExpr(expression.Attr("Status").Equal(dynago.StringValue("pending"))))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		_, err = table.UpdateItem(ctx, pk, dynago.StringValue("shipment#1"), dynago.NewUpdate().Set("Status", "pending"),
+			dynago.WithUpdateConditionExpr(expression.Attr("Status").Equal(dynago.StringValue("pending"))))
+		if !errors.Is(err, dynago.ErrConditionFailed) {
+			t.Errorf("expected ErrConditionFailed; got %v", err)
+		}
+	})
+
+	t.Run("delete and transaction conditions", func(t *testing.T) {
+		shipped := expression.Attr("Status").Equal(dynago.StringValue("delivered"))
+		_, err := table.DeleteItem(ctx, pk, dynago.StringValue("shipment#2"), dynago.WithDeleteConditionExpr(shipped))
+		if !errors.Is(err, dynago.ErrConditionFailed) {
+			t.Errorf("expected ErrConditionFailed; got %v", err)
+		}
+
+		err = table.NewTx().
+			ConditionCheck(pk, dynago.StringValue("shipment#3"), "", nil, dynago.WithTxConditionExpr(shipped)).
+			Delete(pk, dynago.StringValue("shipment#2"), dynago.WithTxConditionExpr(expression.Attr("Tags").Contains(dynago.StringValue("heavy")))).
+			Commit(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var out Shipment
+		err, found := table.GetItem(ctx, pk, dynago.StringValue("shipment#2"), &out)
+		if err != nil || found {
+			t.Errorf("expected shipment two to be deleted; got %v", err)
+		}
+	})
+
+	t.Run("in without values is rejected before sending a request", func(t *testing.T) {
+		// throttledAPI only implements GetItem, any other request panics
+		table := dynago.NewClientFromAPI(throttledAPI{}, dynago.ClientOptions{TableName: "offline", PartitionKeyName: "pk", SortKeyName: "sk"})
+		empty := expression.Attr("Status").In()
+		var out []Shipment
+		if _, err := table.Query(ctx, "", nil, &out, dynago.WithKeyCondition(key), dynago.WithFilterExpr(empty)); !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected Query to return ErrValidation; got %v", err)
+		}
+		if _, err := table.QueryPage(ctx, "", nil, 10, &out, dynago.WithKeyCondition(key), dynago.WithFilterExpr(empty)); !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected QueryPage to return ErrValidation; got %v", err)
+		}
+		if _, err := table.Scan(ctx, &out, dynago.WithScanFilterExpr(empty)); !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected Scan to return ErrValidation; got %v", err)
+		}
+		err := table.PutItem(ctx, pk, dynago.StringValue("shipment#4"), Shipment{Name: "four"}, dynago.WithPutConditionExpr(empty))
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected PutItem to return ErrValidation; got %v", err)
+		}
+		err = table.NewTx().Delete(pk, dynago.StringValue("shipment#1"), dynago.WithTxConditionExpr(empty)).Commit(ctx)
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected transaction to return ErrValidation; got %v", err)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+var cityIndex = dynago.Index{IndexName: "city-index", PartitionKeyName: "City", SortKeyName: "Id"}
+
+// prepareIndexedTable creates a table with a global secondary index on City and Id
+func prepareIndexedTable(t *testing.T) *dynago.Client {
+	t.Helper()
+	ctx := context.TODO()
+	name := getRandomTableName(t)
+	table, err := dynago.NewClient(ctx, dynago.ClientOptions{
+		TableName: name,
+		Endpoint: &dynago.EndpointResolver{
+			EndpointURL:     testdb.Endpoint(),
+			AccessKeyID:     "dummy",
+			SecretAccessKey: "dummy",
+		},
+		PartitionKeyName: "pk",
+		SortKeyName:      "sk",
+		Region:           "us-east-1",
+		Indexes:          []dynago.Index{cityIndex},
+	})
+	if err != nil {
+		t.Fatalf("expected configuration to succeed, got %s", err)
+	}
+
+	stringAttr := func(name string) types.AttributeDefinition {
+		return types.AttributeDefinition{AttributeName: aws.String(name), AttributeType: types.ScalarAttributeTypeS}
+	}
+	err = testdb.CreateTableFromInput(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(name),
+		AttributeDefinitions: []types.AttributeDefinition{
+			stringAttr("pk"), stringAttr("sk"), stringAttr("City"), stringAttr("Id"),
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String(cityIndex.IndexName),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("City"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("Id"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		t.Fatalf("expected table creation to succeed, got %s", err)
+	}
+	return table
+}
+
+func TestNewIndexKeys(t *testing.T) {
+	table := prepareIndexedTable(t)
+
+	keys, err := table.NewIndexKeys("city-index", dynago.StringValue("Sydney"), dynago.StringValue("1"))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(keys) != 2 || keys["City"] == nil || keys["Id"] == nil {
+		t.Errorf("expected City and Id keys; got %v", keys)
+	}
+
+	_, err = table.NewIndexKeys("missing-index", dynago.StringValue("Sydney"), dynago.StringValue("1"))
+	if !errors.Is(err, dynago.ErrValidation) {
+		t.Errorf("expected ErrValidation for unregistered index; got %v", err)
+	}
+}
+
+func TestIndexQueryCursor(t *testing.T) {
+	table := prepareIndexedTable(t)
+	ctx := context.TODO()
+
+	for _, user := range []User{
+		{Id: "1", City: "Sydney", Pk: "users#1", Sk: "user#1"},
+		{Id: "2", City: "Sydney", Pk: "users#2", Sk: "user#2"},
+		{Id: "3", City: "Sydney", Pk: "users#3", Sk: "user#3"},
+	} {
+		err := table.PutItem(ctx, dynago.StringValue(user.Pk), dynago.StringValue(user.Sk), user)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	condition := "City = :city"
+	values := map[string]dynago.Attribute{":city": dynago.StringValue("Sydney")}
+
+	var page []User
+	cursor, err := table.Query(ctx, condition, values, &page, dynago.WithIndex("city-index"), dynago.WithLimit(1))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	for _, name := range []string{"pk", "sk", "City", "Id"} {
+		if cursor[name] == nil {
+			t.Errorf("expected cursor to contain %s; got %v", name, cursor)
+		}
+	}
+
+	t.Run("resume with index cursor", func(t *testing.T) {
+		var out []User
+		_, err := table.Query(ctx, condition, values, &out, dynago.WithIndex("city-index"), dynago.WithCursorKey(cursor))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(out) != 2 || out[0].Id != "2" {
+			t.Errorf("expected to resume after the first item; got %v", out)
+		}
+	})
+
+	t.Run("iterator cursor contains index keys", func(t *testing.T) {
+		it := dynago.NewTable[User](table).QueryIter(ctx, condition, values, dynago.WithIndex("city-index"))
+		for _, err := range it.All() {
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			break
+		}
+		if got := it.Cursor(); len(got) != 4 || got["City"] == nil || got["Id"] == nil {
+			t.Errorf("expected cursor with table and index keys; got %v", got)
+		}
+	})
+
+	tableCursor := table.NewKeys(dynago.StringValue("users#1"), dynago.StringValue("user#1"))
+	t.Run("query rejects cursor without index keys", func(t *testing.T) {
+		var out []User
+		_, err := table.Query(ctx, condition, values, &out, dynago.WithIndex("city-index"), dynago.WithCursorKey(tableCursor))
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+
+	t.Run("iterator rejects cursor without index keys", func(t *testing.T) {
+		it := table.QueryIter(ctx, condition, values, dynago.WithIndex("city-index"), dynago.WithCursorKey(tableCursor))
+		for _, err := range it.All() {
+			if !errors.Is(err, dynago.ErrValidation) {
+				t.Errorf("expected ErrValidation; got %v", err)
+			}
+		}
+	})
+
+	t.Run("table query rejects index cursor", func(t *testing.T) {
+		var out []User
+		_, err := table.Query(ctx, "pk = :pk", map[string]dynago.Attribute{":pk": dynago.StringValue("users#1")}, &out,
+			dynago.WithCursorKey(cursor))
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+
+	t.Run("scan rejects cursor without index keys", func(t *testing.T) {
+		var out []User
+		_, err := table.Scan(ctx, &out, dynago.WithScanIndex("city-index"), dynago.WithScanCursorKey(tableCursor))
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+}
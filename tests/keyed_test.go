@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+type Account struct {
+	ID    string `dynago:"pk=ACCOUNT#{ID},sk=PROFILE"`
+	Email string
+}
+
+type Member struct {
+	AccountID string `dynago:"pk=ACCOUNT#{AccountID}"`
+	UserID    string `dynago:"sk=MEMBER#{UserID}"`
+}
+
+func TestKeyedItems(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	account := Account{ID: "1", Email: "owner@example.com"}
+	if err := table.Put(ctx, account); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	t.Run("put derives keys from tags", func(t *testing.T) {
+		var out Account
+		err, found := table.GetItem(ctx, dynago.StringValue("ACCOUNT#1"), dynago.StringValue("PROFILE"), &out)
+		if err != nil || !found || out != account {
+			t.Errorf("expected account to be stored at templated keys; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("transaction builders derive keys from tags", func(t *testing.T) {
+		err := table.TransactItems(ctx,
+			table.WithPut(Member{AccountID: "1", UserID: "a"}),
+			table.WithPut(Member{AccountID: "1", UserID: "b"}),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		err = table.TransactItems(ctx, table.WithDelete(Member{AccountID: "1", UserID: "a"}))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+
+		var out []Member
+		_, err = table.Query(ctx, "pk = :pk and begins_with(sk, :sk)", map[string]dynago.Attribute{
+			":pk": dynago.StringValue("ACCOUNT#1"),
+			":sk": dynago.StringValue("MEMBER#"),
+		}, &out)
+		if err != nil || len(out) != 1 || out[0].UserID != "b" {
+			t.Errorf("expected only member b; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("delete derives keys from tags", func(t *testing.T) {
+		old, err := table.Delete(ctx, account, dynago.WithDeleteReturnOldValues())
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(old) == 0 {
+			t.Errorf("expected deleted account attributes")
+		}
+	})
+
+	t.Run("item without keys is rejected", func(t *testing.T) {
+		err := table.Put(ctx, User{Id: "1"})
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/pagination"
+	"github.com/oolio-group/dynago/testing/localdb"
+)
+
+type Device struct {
+	Id    int64
+	Name  string
+	Count int
+}
+
+type Blob struct {
+	Account  string
+	Checksum []byte
+	Size     int
+}
+
+func newKeySchemaClient(t *testing.T, opt dynago.ClientOptions) *dynago.Client {
+	t.Helper()
+	opt.TableName = getRandomTableName(t)
+	opt.Region = "us-east-1"
+	opt.Endpoint = &dynago.EndpointResolver{
+		EndpointURL:     testdb.Endpoint(),
+		AccessKeyID:     "dummy",
+		SecretAccessKey: "dummy",
+	}
+	table, err := dynago.NewClient(context.TODO(), opt)
+	if err != nil {
+		t.Fatalf("expected configuration to succeed, got %s", err)
+	}
+	err = testdb.CreateTable(context.TODO(), opt.TableName, opt.PartitionKeyName, opt.SortKeyName,
+		localdb.WithKeyTypes(opt.PartitionKeyType, opt.SortKeyType))
+	if err != nil {
+		t.Fatalf("expected table creation to succeed, got %s", err)
+	}
+	return table
+}
+
+func TestHashOnlyTable(t *testing.T) {
+	table := newKeySchemaClient(t, dynago.ClientOptions{
+		PartitionKeyName: "Id",
+		PartitionKeyType: types.ScalarAttributeTypeN,
+	})
+	ctx := context.TODO()
+
+	for id := int64(1); id <= 3; id++ {
+		err := table.PutItem(ctx, dynago.NumberValue(id), nil, Device{Id: id, Name: "sensor"})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	t.Run("get item", func(t *testing.T) {
+		var out Device
+		err, found := table.GetItem(ctx, dynago.NumberValue(2), nil, &out)
+		if err != nil || !found || out.Id != 2 {
+			t.Errorf("expected device 2; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("update item", func(t *testing.T) {
+		_, err := table.UpdateItem(ctx, dynago.NumberValue(2), nil, dynago.NewUpdate().Add("Count", 1))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var out Device
+		if err, _ := table.GetItem(ctx, dynago.NumberValue(2), nil, &out); err != nil || out.Count != 1 {
+			t.Errorf("expected count to be incremented; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		var out []Device
+		_, err := table.Query(ctx, "Id = :id", map[string]dynago.Attribute{":id": dynago.NumberValue(3)}, &out)
+		if err != nil || len(out) != 1 || out[0].Id != 3 {
+			t.Errorf("expected device 3; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("batch get", func(t *testing.T) {
+		var out []Device
+		err := table.BatchGetItems(ctx, []dynago.AttributeRecord{
+			table.NewKeys(dynago.NumberValue(1), nil),
+			table.NewKeys(dynago.NumberValue(3), nil),
+		}, &out)
+		if err != nil || len(out) != 2 {
+			t.Errorf("expected two devices; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("paginated scan with encoded cursor", func(t *testing.T) {
+		seen := map[int64]bool{}
+		var encoded string
+		for {
+			cursor, err := pagination.DecodeKeys(table, encoded)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			var out []Device
+			cursor, err = table.Scan(ctx, &out, dynago.WithScanLimit(1), dynago.WithScanCursorKey(cursor))
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			for _, d := range out {
+				seen[d.Id] = true
+			}
+			if encoded, err = pagination.EncodeKeys(table, cursor); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if encoded == "" {
+				break
+			}
+		}
+		if len(seen) != 3 {
+			t.Errorf("expected to scan every device; got %v", seen)
+		}
+	})
+
+	t.Run("delete item", func(t *testing.T) {
+		_, err := table.DeleteItem(ctx, dynago.NumberValue(1), nil)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var out Device
+		if err, found := table.GetItem(ctx, dynago.NumberValue(1), nil, &out); err != nil || found {
+			t.Errorf("expected device to be deleted; got %v, %v", out, err)
+		}
+	})
+}
+
+func TestBinarySortKey(t *testing.T) {
+	table := newKeySchemaClient(t, dynago.ClientOptions{
+		PartitionKeyName: "Account",
+		SortKeyName:      "Checksum",
+		SortKeyType:      types.ScalarAttributeTypeB,
+	})
+	ctx := context.TODO()
+	owner := dynago.StringValue("user#1")
+
+	w := table.NewBatchWriter(ctx, dynago.BatchWriterOptions{})
+	for _, checksum := range [][]byte{{3}, {1}, {2}} {
+		if err := w.Put(owner, &types.AttributeValueMemberB{Value: checksum}, Blob{Size: int(checksum[0])}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	var page []Blob
+	cursor, err := table.Query(ctx, "Account = :account", map[string]dynago.Attribute{":account": owner}, &page, dynago.WithLimit(2))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(page) != 2 || !bytes.Equal(page[0].Checksum, []byte{1}) || !bytes.Equal(page[1].Checksum, []byte{2}) {
+		t.Errorf("expected blobs sorted by checksum; got %v", page)
+	}
+
+	encoded, err := pagination.EncodeKeys(table, cursor)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	cursor, err = pagination.DecodeKeys(table, encoded)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var next []Blob
+	_, err = table.Query(ctx, "Account = :account", map[string]dynago.Attribute{":account": owner}, &next, dynago.WithCursorKey(cursor))
+	if err != nil || len(next) != 1 || !bytes.Equal(next[0].Checksum, []byte{3}) {
+		t.Errorf("expected last blob; got %v, %v", next, err)
+	}
+
+	_, err = table.Query(ctx, "Account = :account", map[string]dynago.Attribute{":account": owner}, &next,
+		dynago.WithCursorKey(table.NewKeys(owner, dynago.StringValue("not-binary"))))
+	if !errors.Is(err, dynago.ErrValidation) {
+		t.Errorf("expected cursor with wrong key type to be rejected; got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+func newLoggedClient(t *testing.T, table *dynago.Client, logger *slog.Logger, logKeyValues bool) *dynago.Client {
+	t.Helper()
+	client, err := dynago.NewClient(context.TODO(), dynago.ClientOptions{
+		TableName: table.TableName,
+		Endpoint: &dynago.EndpointResolver{
+			EndpointURL:     testdb.Endpoint(),
+			AccessKeyID:     "dummy",
+			SecretAccessKey: "dummy",
+		},
+		PartitionKeyName: "pk",
+		SortKeyName:      "sk",
+		Region:           "us-east-1",
+		Logger:           logger,
+		LogKeyValues:     logKeyValues,
+	})
+	if err != nil {
+		t.Fatalf("expected configuration to succeed, got %s", err)
+	}
+	return client
+}
+
+func TestLogger(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk, sk := dynago.StringValue("users#secret"), dynago.StringValue("user#secret")
+
+	testCases := []struct {
+		title        string
+		level        slog.Level
+		logKeyValues bool
+		expected     []string
+		unexpected   []string
+	}{
+		{
+			title:      "not found is logged at debug level with redacted keys",
+			level:      slog.LevelDebug,
+			expected:   []string{"level=DEBUG", `msg="item not found"`, "key.pk=[REDACTED]", "table=" + table.TableName},
+			unexpected: []string{"users#secret"},
+		},
+		{
+			title:        "key values are logged when enabled",
+			level:        slog.LevelDebug,
+			logKeyValues: true,
+			expected:     []string{"key.pk=users#secret", "key.sk=user#secret"},
+		},
+		{
+			title:      "not found is not logged at info level",
+			level:      slog.LevelInfo,
+			unexpected: []string{"item not found"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: tc.level}))
+			client := newLoggedClient(t, table, logger, tc.logKeyValues)
+
+			var out User
+			err, found := client.GetItem(ctx, pk, sk, &out)
+			if err != nil || found {
+				t.Fatalf("expected item not to be found; got %v", err)
+			}
+			for _, s := range tc.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected log to contain %s; got %s", s, buf.String())
+				}
+			}
+			for _, s := range tc.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("expected log not to contain %s; got %s", s, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerSilentByDefault(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	var out User
+	table.GetItem(ctx, dynago.StringValue("users#missing"), dynago.StringValue("user#missing"), &out)
+	_, _ = table.Query(ctx, "pk = = :pk", map[string]dynago.Attribute{":pk": dynago.StringValue("users")}, &out)
+	if buf.Len() > 0 {
+		t.Errorf("expected nothing to be logged; got %s", buf.String())
+	}
+}
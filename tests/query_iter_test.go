@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+func TestQueryIter(t *testing.T) {
+	client := prepareTable(t)
+	table := dynago.NewTable[User](client)
+	ctx := context.TODO()
+
+	source := make([]User, 0, 30)
+	items := make([]*dynago.TransactPutItemsInput, 0, 30)
+	for idx := range 30 {
+		user := User{
+			Id:   fmt.Sprintf("%d", idx),
+			City: []string{"Melbourne", "Sydney", "Perth"}[idx%3],
+			Pk:   "users#iter",
+			Sk:   fmt.Sprintf("user#%02d", idx),
+		}
+		source = append(source, user)
+		items = append(items, &dynago.TransactPutItemsInput{
+			PartitionKeyValue: dynago.StringValue(user.Pk),
+			SortKeyValue:      dynago.StringValue(user.Sk),
+			Item:              user,
+		})
+	}
+	if err := client.TransactPutItems(ctx, items); err != nil {
+		t.Fatalf("prepare table failed; got %s", err)
+	}
+	values := map[string]dynago.Attribute{":pk": dynago.StringValue("users#iter")}
+
+	t.Run("iterate all items", func(t *testing.T) {
+		it := table.QueryIter(ctx, "pk = :pk", values)
+		var got []User
+		for user, err := range it.All() {
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			got = append(got, user)
+		}
+		if !reflect.DeepEqual(source, got) {
+			t.Errorf("expected %v; got %v", source, got)
+		}
+		if it.Cursor() != nil {
+			t.Errorf("expected no cursor after consuming all items; got %v", it.Cursor())
+		}
+	})
+
+	t.Run("stop early and resume from cursor", func(t *testing.T) {
+		var got []User
+		var cursor map[string]dynago.Attribute
+		for {
+			it := table.QueryIter(ctx, "pk = :pk", values, dynago.WithCursorKey(cursor))
+			count := 0
+			for user, err := range it.All() {
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+				got = append(got, user)
+				count++
+				if count == 7 {
+					break
+				}
+			}
+			cursor = it.Cursor()
+			if cursor == nil {
+				break
+			}
+		}
+		if !reflect.DeepEqual(source, got) {
+			t.Errorf("expected %v; got %v", source, got)
+		}
+	})
+
+	t.Run("limit with filter", func(t *testing.T) {
+		it := table.QueryIter(ctx, "pk = :pk", map[string]dynago.Attribute{
+			":pk":   dynago.StringValue("users#iter"),
+			":city": dynago.StringValue("Sydney"),
+		}, dynago.WithFilter("City = :city"), dynago.WithLimit(4))
+		var got []User
+		for user, err := range it.All() {
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			got = append(got, user)
+		}
+		expected := []User{source[1], source[4], source[7], source[10]}
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("expected %v; got %v", expected, got)
+		}
+
+		// resuming continues right after the last item returned
+		next := table.QueryIter(ctx, "pk = :pk", map[string]dynago.Attribute{
+			":pk":   dynago.StringValue("users#iter"),
+			":city": dynago.StringValue("Sydney"),
+		}, dynago.WithFilter("City = :city"), dynago.WithLimit(1), dynago.WithCursorKey(it.Cursor()))
+		for user, err := range next.All() {
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if !reflect.DeepEqual(source[13], user) {
+				t.Errorf("expected %v; got %v", source[13], user)
+			}
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		it := client.QueryIter(ctx, "pk - invalid", nil)
+		var failed bool
+		for _, err := range it.All() {
+			if err == nil {
+				t.Fatal("expected query to fail")
+			}
+			failed = true
+		}
+		if !failed {
+			t.Error("expected query error to be yielded")
+		}
+	})
+
+	t.Run("unregistered index", func(t *testing.T) {
+		it := client.QueryIter(ctx, "City = :city", map[string]dynago.Attribute{":city": dynago.StringValue("Sydney")},
+			dynago.WithIndex("unregistered-index"))
+		var failed bool
+		for _, err := range it.All() {
+			if !errors.Is(err, dynago.ErrValidation) {
+				t.Fatalf("expected ErrValidation; got %v", err)
+			}
+			failed = true
+		}
+		if !failed {
+			t.Error("expected validation error to be yielded")
+		}
+	})
+
+	t.Run("unmarshal error stops iteration", func(t *testing.T) {
+		type numericCity struct {
+			City int
+		}
+		it := dynago.NewTable[numericCity](client).QueryIter(ctx, "pk = :pk", values)
+		var yielded, failed int
+		for _, err := range it.All() {
+			yielded++
+			if err != nil {
+				failed++
+			}
+		}
+		if yielded != 1 || failed != 1 {
+			t.Errorf("expected a single error; got %d results with %d errors", yielded, failed)
+		}
+		for range it.All() {
+			t.Fatal("expected iteration to stay stopped")
+		}
+	})
+}
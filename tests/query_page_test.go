@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/testing/memdb"
+)
+
+// countingAPI counts the Query requests sent to the wrapped API
+type countingAPI struct {
+	dynago.DynamoDBAPI
+	queries int
+}
+
+func (api *countingAPI) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	api.queries++
+	return api.DynamoDBAPI.Query(ctx, params, optFns...)
+}
+
+func TestQueryPage(t *testing.T) {
+	client := prepareTable(t)
+	table := dynago.NewTable[User](client)
+	ctx := context.TODO()
+
+	var matching []User
+	items := make([]*dynago.TransactPutItemsInput, 0, 20)
+	for idx := range 20 {
+		user := User{
+			Id:   fmt.Sprintf("%d", idx),
+			City: []string{"Melbourne", "Sydney", "Perth", "Perth"}[idx%4],
+			Pk:   "users#page",
+			Sk:   fmt.Sprintf("user#%02d", idx),
+		}
+		if user.City == "Perth" {
+			matching = append(matching, user)
+		}
+		items = append(items, &dynago.TransactPutItemsInput{
+			PartitionKeyValue: dynago.StringValue(user.Pk),
+			SortKeyValue:      dynago.StringValue(user.Sk),
+			Item:              user,
+		})
+	}
+	if err := client.TransactPutItems(ctx, items); err != nil {
+		t.Fatalf("prepare table failed; got %s", err)
+	}
+	values := map[string]dynago.Attribute{
+		":pk":   dynago.StringValue("users#page"),
+		":city": dynago.StringValue("Perth"),
+	}
+
+	pages := func(t *testing.T, pageSize int32, opts ...dynago.QueryOptions) (got []User, sizes []int) {
+		t.Helper()
+		var cursor map[string]dynago.Attribute
+		for {
+			page, next, err := table.QueryPage(ctx, "pk = :pk", values, pageSize,
+				append([]dynago.QueryOptions{dynago.WithFilter("City = :city"), dynago.WithCursorKey(cursor)}, opts...)...)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			got = append(got, page...)
+			sizes = append(sizes, len(page))
+			if next == nil {
+				return got, sizes
+			}
+			cursor = next
+		}
+	}
+
+	t.Run("pages hold page size matching items", func(t *testing.T) {
+		got, sizes := pages(t, 4)
+		if !reflect.DeepEqual(matching, got) {
+			t.Errorf("expected every matching user once; got %v", got)
+		}
+		if !reflect.DeepEqual(sizes, []int{4, 4, 2}) {
+			t.Errorf("expected pages of 4 items; got %v", sizes)
+		}
+	})
+
+	t.Run("limit sets the items evaluated per request", func(t *testing.T) {
+		got, sizes := pages(t, 3, dynago.WithLimit(5))
+		if !reflect.DeepEqual(matching, got) {
+			t.Errorf("expected every matching user once; got %v", got)
+		}
+		if !reflect.DeepEqual(sizes, []int{3, 3, 3, 1}) {
+			t.Errorf("expected pages of 3 items; got %v", sizes)
+		}
+	})
+
+	t.Run("cursor is nil when the last page is full", func(t *testing.T) {
+		_, sizes := pages(t, 10)
+		if !reflect.DeepEqual(sizes, []int{10}) {
+			t.Errorf("expected a single page; got %v", sizes)
+		}
+	})
+
+	t.Run("page size must be positive", func(t *testing.T) {
+		_, _, err := table.QueryPage(ctx, "pk = :pk", values, 0)
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+}
+
+func TestQueryPageRequests(t *testing.T) {
+	ctx := context.TODO()
+	db := memdb.New()
+	if err := db.CreateTable(ctx, "users", "pk", "sk"); err != nil {
+		t.Fatal(err)
+	}
+	api := &countingAPI{DynamoDBAPI: db}
+	client := dynago.NewClientFromAPI(api, dynago.ClientOptions{TableName: "users", PartitionKeyName: "pk", SortKeyName: "sk"})
+
+	items := make([]map[string]dynago.Attribute, 1000)
+	for idx := range items {
+		city := "Melbourne"
+		if idx%100 == 0 {
+			city = "Perth"
+		}
+		items[idx] = map[string]dynago.Attribute{
+			"pk":   dynago.StringValue("users"),
+			"sk":   dynago.StringValue(fmt.Sprintf("user#%04d", idx)),
+			"City": dynago.StringValue(city),
+		}
+	}
+	if err := client.BatchWriteItems(ctx, items); err != nil {
+		t.Fatalf("prepare table failed; got %s", err)
+	}
+
+	values := map[string]dynago.Attribute{":pk": dynago.StringValue("users"), ":city": dynago.StringValue("Perth")}
+	var cursor map[string]dynago.Attribute
+	var sizes []int
+	for {
+		var out []map[string]dynago.Attribute
+		next, err := client.QueryPage(ctx, "pk = :pk", values, 5, &out, dynago.WithFilter("City = :city"), dynago.WithCursorKey(cursor))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		sizes = append(sizes, len(out))
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	// a selective filter does not make requests evaluate fewer items, each page is read with a single request
+	if !reflect.DeepEqual(sizes, []int{5, 5}) || api.queries != 2 {
+		t.Errorf("expected 2 pages of 5 items read with 2 requests; got %v with %d requests", sizes, api.queries)
+	}
+}
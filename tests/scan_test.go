@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+func prepareScanTable(t *testing.T, count int) *dynago.Client {
+	t.Helper()
+	table := prepareTable(t)
+	items := make([]*dynago.TransactPutItemsInput, 0, count)
+	for idx := range count {
+		user := User{
+			Id:   fmt.Sprintf("%d", idx),
+			City: []string{"Melbourne", "Sydney"}[idx%2],
+			Pk:   fmt.Sprintf("users#%d", idx%7),
+			Sk:   fmt.Sprintf("user#%03d", idx),
+		}
+		items = append(items, &dynago.TransactPutItemsInput{
+			PartitionKeyValue: dynago.StringValue(user.Pk),
+			SortKeyValue:      dynago.StringValue(user.Sk),
+			Item:              user,
+		})
+		if len(items) == 100 || idx == count-1 {
+			if err := table.TransactPutItems(context.TODO(), items); err != nil {
+				t.Fatalf("prepare table failed; got %s", err)
+			}
+			items = items[:0]
+		}
+	}
+	return table
+}
+
+func TestScan(t *testing.T) {
+	table := prepareScanTable(t, 50)
+	ctx := context.TODO()
+
+	t.Run("scan all items", func(t *testing.T) {
+		var out []User
+		cursor, err := table.Scan(ctx, &out)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if cursor != nil {
+			t.Errorf("expected scan to finish; got cursor %v", cursor)
+		}
+		if len(out) != 50 {
+			t.Errorf("expected 50 items; got %d", len(out))
+		}
+	})
+
+	t.Run("scan with filter and projection", func(t *testing.T) {
+		var out []User
+		_, err := table.Scan(ctx, &out,
+			dynago.WithScanFilter("City = :city", map[string]dynago.Attribute{":city": dynago.StringValue("Sydney")}),
+			dynago.WithScanFields([]string{"Id", "City"}),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(out) != 25 {
+			t.Errorf("expected 25 items; got %d", len(out))
+		}
+		for _, user := range out {
+			if user.City != "Sydney" || user.Pk != "" {
+				t.Errorf("expected projected Sydney users only; got %v", user)
+			}
+		}
+	})
+
+	t.Run("filter values are merged", func(t *testing.T) {
+		var out []User
+		_, err := table.Scan(ctx, &out,
+			dynago.WithScanFilter("City = :city", map[string]dynago.Attribute{":city": dynago.StringValue("Sydney")}),
+			dynago.WithScanFilter("City = :city AND Id = :id", map[string]dynago.Attribute{":id": dynago.StringValue("1")}),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(out) != 1 || out[0].Id != "1" {
+			t.Errorf("expected user 1; got %v", out)
+		}
+
+		city := map[string]dynago.Attribute{":city": dynago.StringValue("Sydney")}
+		_, err = table.Scan(ctx, &out, dynago.WithScanFilter("City = :city", city), dynago.WithScanFilter("City = :city", city))
+		if err == nil {
+			t.Errorf("expected conflicting filter values to fail")
+		}
+	})
+
+	t.Run("scan pages with limit and cursor", func(t *testing.T) {
+		seen := map[string]bool{}
+		var cursor map[string]dynago.Attribute
+		for {
+			var out []User
+			next, err := table.Scan(ctx, &out, dynago.WithScanLimit(20), dynago.WithScanCursorKey(cursor))
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			for _, user := range out {
+				if seen[user.Id] {
+					t.Fatalf("found duplicate item %s", user.Id)
+				}
+				seen[user.Id] = true
+			}
+			if next == nil {
+				break
+			}
+			cursor = next
+		}
+		if len(seen) != 50 {
+			t.Errorf("expected 50 items; got %d", len(seen))
+		}
+	})
+}
+
+func TestParallelScan(t *testing.T) {
+	table := prepareScanTable(t, 200)
+	ctx := context.TODO()
+
+	t.Run("scan all segments", func(t *testing.T) {
+		var (
+			mu  sync.Mutex
+			ids []string
+		)
+		cursors, err := dynago.NewTable[User](table).ParallelScan(ctx, 4, nil, func(ctx context.Context, segment int32, items []User) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, user := range items {
+				ids = append(ids, user.Id)
+			}
+			return nil
+		}, dynago.WithScanLimit(10))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		for segment, cursor := range cursors {
+			if cursor != nil {
+				t.Errorf("expected segment %d to finish; got cursor %v", segment, cursor)
+			}
+		}
+		if len(ids) != 200 {
+			t.Errorf("expected 200 items; got %d", len(ids))
+		}
+	})
+
+	t.Run("resume after handler error", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			seen   = map[string]int{}
+			failed bool
+		)
+		errStop := errors.New("stop")
+		handler := func(ctx context.Context, segment int32, items []map[string]dynago.Attribute) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !failed && segment == 1 {
+				failed = true
+				return errStop
+			}
+			for _, item := range items {
+				seen[item["Id"].(*types.AttributeValueMemberS).Value]++
+			}
+			return nil
+		}
+
+		cursors, err := table.ParallelScan(ctx, 3, nil, handler, dynago.WithScanLimit(15))
+		if !errors.Is(err, errStop) {
+			t.Fatalf("expected handler error; got %v", err)
+		}
+		if len(cursors) != 3 || cursors[1] == nil {
+			t.Fatalf("expected resumable cursor for failed segment; got %v", cursors)
+		}
+
+		cursors, err = table.ParallelScan(ctx, 3, cursors, handler, dynago.WithScanLimit(15))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		for segment, cursor := range cursors {
+			if cursor != nil {
+				t.Errorf("expected segment %d to finish; got cursor %v", segment, cursor)
+			}
+		}
+		if len(seen) != 200 {
+			t.Errorf("expected 200 items; got %d", len(seen))
+		}
+		for id, count := range seen {
+			if count != 1 {
+				t.Errorf("expected item %s to be handled once; got %d", id, count)
+			}
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := table.ParallelScan(ctx, 2, nil, func(ctx context.Context, segment int32, items []map[string]dynago.Attribute) error {
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context cancelled error; got %v", err)
+		}
+	})
+}
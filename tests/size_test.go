@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+type Document struct {
+	Id   string
+	Body string
+}
+
+func TestItemSizeLimits(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("docs")
+	large := Document{Id: "large", Body: strings.Repeat("x", dynago.MaxItemSize)}
+	small := Document{Id: "small", Body: "x"}
+
+	expectItemSizeError := func(t *testing.T, err error, index int) {
+		t.Helper()
+		var sizeErr *dynago.ItemSizeError
+		if !errors.As(err, &sizeErr) {
+			t.Fatalf("expected ItemSizeError; got %v", err)
+		}
+		if sizeErr.Index != index || sizeErr.Size <= dynago.MaxItemSize {
+			t.Errorf("expected item %d to be oversized; got %v", index, sizeErr)
+		}
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ItemSizeError to match ErrValidation")
+		}
+	}
+
+	t.Run("put item", func(t *testing.T) {
+		err := table.PutItem(ctx, pk, dynago.StringValue("large"), large)
+		expectItemSizeError(t, err, 0)
+	})
+
+	t.Run("transact put items", func(t *testing.T) {
+		err := table.TransactPutItems(ctx, []*dynago.TransactPutItemsInput{
+			{PartitionKeyValue: pk, SortKeyValue: dynago.StringValue("small"), Item: small},
+			{PartitionKeyValue: pk, SortKeyValue: dynago.StringValue("large"), Item: large},
+		})
+		expectItemSizeError(t, err, 1)
+	})
+
+	t.Run("batch write items", func(t *testing.T) {
+		items := []dynago.AttributeRecord{
+			{"pk": pk, "sk": dynago.StringValue("small"), "Body": dynago.StringValue("x")},
+			{"pk": pk, "sk": dynago.StringValue("small2"), "Body": dynago.StringValue("x")},
+			{"pk": pk, "sk": dynago.StringValue("large"), "Body": dynago.StringValue(large.Body)},
+		}
+		err := table.BatchWriteItems(ctx, items)
+		expectItemSizeError(t, err, 2)
+	})
+
+	t.Run("batch writer", func(t *testing.T) {
+		w := table.NewBatchWriter(ctx, dynago.BatchWriterOptions{})
+		if err := w.Put(pk, dynago.StringValue("small"), small); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		expectItemSizeError(t, w.Put(pk, dynago.StringValue("large"), large), 1)
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	})
+
+	t.Run("too many transaction items", func(t *testing.T) {
+		items := make([]types.TransactWriteItem, dynago.MaxTransactionItems+1)
+		for i := range items {
+			items[i] = table.WithDeleteItem(pk, dynago.NumberValue(int64(i)))
+		}
+		err := table.TransactItems(ctx, items...)
+		var txErr *dynago.TransactionSizeError
+		if !errors.As(err, &txErr) || txErr.Items != len(items) {
+			t.Errorf("expected TransactionSizeError; got %v", err)
+		}
+	})
+
+	t.Run("empty transaction item", func(t *testing.T) {
+		// helpers that fail to build an item return an empty item
+		for _, item := range []types.TransactWriteItem{
+			{},
+			table.WithUpdateItem(pk, pk, dynago.NewUpdate()),
+			table.WithPut(User{Id: "1"}),
+		} {
+			err := table.TransactItems(ctx, table.WithDeleteItem(pk, pk), item)
+			if !errors.Is(err, dynago.ErrValidation) || !strings.Contains(err.Error(), "item 1") {
+				t.Errorf("expected ErrValidation naming item 1; got %v", err)
+			}
+		}
+	})
+
+	t.Run("transaction too large", func(t *testing.T) {
+		body := strings.Repeat("x", dynago.MaxItemSize-100)
+		tx := table.NewTx()
+		for i := 0; i < 11; i++ {
+			tx.Put(pk, dynago.NumberValue(int64(i)), Document{Body: body})
+		}
+		err := tx.Commit(ctx)
+		var txErr *dynago.TransactionSizeError
+		if !errors.As(err, &txErr) || txErr.Size <= dynago.MaxTransactionSize {
+			t.Errorf("expected TransactionSizeError; got %v", err)
+		}
+	})
+
+	var out Document
+	err, found := table.GetItem(ctx, pk, dynago.StringValue("large"), &out)
+	if err != nil || found {
+		t.Errorf("expected oversized item not to be written; got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+func TestTable(t *testing.T) {
+	client := prepareTable(t)
+	table := dynago.NewTable[User](client)
+	ctx := context.TODO()
+
+	source := []User{
+		{Id: "1", City: "Melbourne", Pk: "users#table_test", Sk: "user#1"},
+		{Id: "2", City: "Sydney", Pk: "users#table_test", Sk: "user#2"},
+		{Id: "3", City: "Perth", Pk: "users#table_test", Sk: "user#3"},
+	}
+	for _, user := range source {
+		err := table.Put(ctx, dynago.StringValue(user.Pk), dynago.StringValue(user.Sk), user)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	t.Run("get existing item", func(t *testing.T) {
+		got, found, err := table.Get(ctx, dynago.StringValue("users#table_test"), dynago.StringValue("user#2"))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if !found {
+			t.Fatal("expected item to be found")
+		}
+		if !reflect.DeepEqual(source[1], got) {
+			t.Errorf("expected %v; got %v", source[1], got)
+		}
+	})
+
+	t.Run("get missing item", func(t *testing.T) {
+		got, found, err := table.Get(ctx, dynago.StringValue("users#table_test"), dynago.StringValue("user#404"))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if found {
+			t.Errorf("expected item not to be found; got %v", got)
+		}
+		if !reflect.DeepEqual(User{}, got) {
+			t.Errorf("expected zero value; got %v", got)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		got, cursor, err := table.Query(ctx, "pk = :pk", map[string]dynago.Attribute{
+			":pk": dynago.StringValue("users#table_test"),
+		}, dynago.WithLimit(2))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if !reflect.DeepEqual(source[:2], got) {
+			t.Errorf("expected %v; got %v", source[:2], got)
+		}
+		if cursor == nil {
+			t.Fatal("expected cursor for next page")
+		}
+
+		got, _, err = table.Query(ctx, "pk = :pk", map[string]dynago.Attribute{
+			":pk": dynago.StringValue("users#table_test"),
+		}, dynago.WithLimit(2), dynago.WithCursorKey(cursor))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if !reflect.DeepEqual(source[2:], got) {
+			t.Errorf("expected %v; got %v", source[2:], got)
+		}
+	})
+
+	t.Run("batch get", func(t *testing.T) {
+		keys := []dynago.AttributeRecord{
+			client.NewKeys(dynago.StringValue("users#table_test"), dynago.StringValue("user#1")),
+			client.NewKeys(dynago.StringValue("users#table_test"), dynago.StringValue("user#3")),
+			client.NewKeys(dynago.StringValue("users#table_test"), dynago.StringValue("user#404")),
+		}
+		got, err := table.BatchGet(ctx, keys)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 items; got %v", got)
+		}
+		ids := map[string]bool{}
+		for _, user := range got {
+			ids[user.Id] = true
+		}
+		if !ids["1"] || !ids["3"] {
+			t.Errorf("expected users 1 and 3; got %v", got)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/oolio-group/dynago"
+	"github.com/oolio-group/dynago/expression"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTelemetry(t *testing.T) {
+	ctx := context.TODO()
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	base := prepareTable(t)
+	table := base.ForTable(dynago.ClientOptions{
+		TableName:        base.TableName,
+		PartitionKeyName: "pk",
+		SortKeyName:      "sk",
+		TracerProvider:   sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:    sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+
+	lastSpan := func(t *testing.T, name string) map[attribute.Key]attribute.Value {
+		t.Helper()
+		ended := spans.Ended()
+		for i := len(ended) - 1; i >= 0; i-- {
+			if ended[i].Name() == name {
+				attrs := map[attribute.Key]attribute.Value{}
+				for _, kv := range ended[i].Attributes() {
+					attrs[kv.Key] = kv.Value
+				}
+				return attrs
+			}
+		}
+		t.Fatalf("expected a %s span", name)
+		return nil
+	}
+	expectInt := func(t *testing.T, attrs map[attribute.Key]attribute.Value, key attribute.Key, expected int64) {
+		t.Helper()
+		if got := attrs[key].AsInt64(); got != expected {
+			t.Errorf("expected %s to be %d; got %d", key, expected, got)
+		}
+	}
+
+	t.Run("batch write chunks", func(t *testing.T) {
+		items := make([]map[string]dynago.Attribute, 30)
+		for idx := range items {
+			items[idx], _ = attributevalue.MarshalMap(User{Id: fmt.Sprint(idx), City: "Perth"})
+			for k, v := range table.NewKeys(dynago.StringValue("users"), dynago.StringValue(fmt.Sprintf("user#%02d", idx))) {
+				items[idx][k] = v
+			}
+		}
+		if err := table.BatchWriteItems(ctx, items); err != nil {
+			t.Fatal(err)
+		}
+		attrs := lastSpan(t, "BatchWriteItems")
+		expectInt(t, attrs, dynago.AttrChunks, 2)
+		expectInt(t, attrs, dynago.AttrItems, 30)
+		if got := attrs[dynago.AttrConsumedCapacity].AsFloat64(); got <= 0 {
+			t.Errorf("expected consumed capacity; got %v", got)
+		}
+		if got := attrs[dynago.AttrTableNames].AsStringSlice(); len(got) != 1 || got[0] != table.TableName {
+			t.Errorf("expected table names [%s]; got %v", table.TableName, got)
+		}
+	})
+
+	t.Run("query pages", func(t *testing.T) {
+		var users []User
+		_, err := table.QueryPage(ctx, "pk = :pk", map[string]dynago.Attribute{":pk": dynago.StringValue("users")}, 6, &users, dynago.WithLimit(4))
+		if err != nil {
+			t.Fatal(err)
+		}
+		attrs := lastSpan(t, "QueryPage")
+		expectInt(t, attrs, dynago.AttrPages, 2)
+		expectInt(t, attrs, dynago.AttrItems, 6)
+	})
+
+	t.Run("query iterator pages", func(t *testing.T) {
+		// the filter skips most items evaluated, so the two items are read from several pages of the same operation
+		values := map[string]dynago.Attribute{":pk": dynago.StringValue("users"), ":one": dynago.StringValue("1")}
+		it := table.QueryIter(ctx, "pk = :pk", values, dynago.WithFilter("begins_with(Id, :one)"), dynago.WithLimit(2))
+		const processing = 150 * time.Millisecond
+		for _, err := range it.All() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(processing)
+		}
+		attrs := lastSpan(t, "QueryIter")
+		if got := attrs[dynago.AttrPages].AsInt64(); got < 2 {
+			t.Errorf("expected several pages; got %d", got)
+		}
+		expectInt(t, attrs, dynago.AttrItems, 2)
+
+		// processing items is not part of the recorded duration
+		var data metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &data); err != nil {
+			t.Fatal(err)
+		}
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if m.Name != dynago.MetricOperationDuration {
+					continue
+				}
+				for _, point := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					if op, _ := point.Attributes.Value(dynago.AttrOperation); op.AsString() == "QueryIter" && point.Sum >= (2*processing).Seconds() {
+						t.Errorf("expected QueryIter duration to exclude processing; got %fs", point.Sum)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("get item", func(t *testing.T) {
+		var user User
+		if err, found := table.GetItem(ctx, dynago.StringValue("users"), dynago.StringValue("user#01"), &user); err != nil || !found {
+			t.Fatalf("expected item; got %v %v", err, found)
+		}
+		attrs := lastSpan(t, "GetItem")
+		expectInt(t, attrs, dynago.AttrItems, 1)
+		if got := attrs[dynago.AttrOperation].AsString(); got != "GetItem" {
+			t.Errorf("expected operation GetItem; got %s", got)
+		}
+	})
+
+	t.Run("derived client", func(t *testing.T) {
+		derived := table.ForTable(dynago.ClientOptions{TableName: table.TableName, PartitionKeyName: "pk", SortKeyName: "sk"})
+		if _, err := derived.DeleteItem(ctx, dynago.StringValue("users"), dynago.StringValue("user#98")); err != nil {
+			t.Fatal(err)
+		}
+		attrs := lastSpan(t, "DeleteItem")
+		if got := attrs[dynago.AttrOperation].AsString(); got != "DeleteItem" {
+			t.Errorf("expected operation DeleteItem; got %s", got)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		err := table.NewTx().
+			Put(dynago.StringValue("users"), dynago.StringValue("user#99"), User{Id: "99"}).
+			Delete(dynago.StringValue("users"), dynago.StringValue("user#00")).
+			Commit(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attrs := lastSpan(t, "Tx.Commit")
+		expectInt(t, attrs, dynago.AttrItems, 2)
+		expectInt(t, attrs, dynago.AttrAttempts, 1)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := table.PutItem(ctx, dynago.StringValue("users"), dynago.StringValue("user#01"), User{Id: "1"},
+			dynago.WithPutConditionExpr(expression.AttributeNotExists("pk")))
+		if !errors.Is(err, dynago.ErrConditionFailed) {
+			t.Fatalf("expected ErrConditionFailed; got %v", err)
+		}
+		ended := spans.Ended()
+		span := ended[len(ended)-1]
+		if span.Name() != "PutItem" || span.Status().Code != codes.Error {
+			t.Errorf("expected failed PutItem span; got %s %v", span.Name(), span.Status())
+		}
+		if got := lastSpan(t, "PutItem")[dynago.AttrErrorType].AsString(); got != "condition_failed" {
+			t.Errorf("expected error type condition_failed; got %s", got)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		var data metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &data); err != nil {
+			t.Fatal(err)
+		}
+		durations := map[string]uint64{}
+		failures := map[string]int64{}
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				switch m.Name {
+				case dynago.MetricOperationDuration:
+					for _, point := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+						op, _ := point.Attributes.Value(dynago.AttrOperation)
+						durations[op.AsString()] += point.Count
+					}
+				case dynago.MetricOperationErrors:
+					for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+						op, _ := point.Attributes.Value(dynago.AttrOperation)
+						kind, _ := point.Attributes.Value(dynago.AttrErrorType)
+						failures[op.AsString()+" "+kind.AsString()] += point.Value
+					}
+				}
+			}
+		}
+		for _, op := range []string{"BatchWriteItems", "QueryPage", "QueryIter", "GetItem", "Tx.Commit", "PutItem"} {
+			if durations[op] != 1 {
+				t.Errorf("expected 1 duration of %s; got %d", op, durations[op])
+			}
+		}
+		if len(failures) != 1 || failures["PutItem condition_failed"] != 1 {
+			t.Errorf("expected 1 failed PutItem; got %v", failures)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oolio-group/dynago"
+)
+
+func TestTransactGetItems(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("users#snapshot")
+
+	for _, user := range []User{
+		{Id: "1", City: "Sydney", Age: 30},
+		{Id: "2", City: "Perth", Age: 40},
+	} {
+		err := table.PutItem(ctx, pk, dynago.StringValue("user#"+user.Id), user)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	t.Run("items are read in request order with projections", func(t *testing.T) {
+		var first, missing, second User
+		found, err := table.TransactGetItems(ctx, []*dynago.TransactGetItemsInput{
+			{Key: table.NewKeys(pk, dynago.StringValue("user#2")), Out: &second},
+			{Key: table.NewKeys(pk, dynago.StringValue("user#missing")), Out: &missing},
+			{Key: table.NewKeys(pk, dynago.StringValue("user#1")), Fields: []string{"Id", "City"}, Out: &first},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(found) != 3 || !found[0] || found[1] || !found[2] {
+			t.Errorf("expected only the second item to be missing; got %v", found)
+		}
+		if second.Id != "2" || second.Age != 40 {
+			t.Errorf("expected every attribute of user 2; got %v", second)
+		}
+		if first.Id != "1" || first.City != "Sydney" || first.Age != 0 || first.Pk != "" {
+			t.Errorf("expected projected attributes of user 1; got %v", first)
+		}
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		users := dynago.NewTable[User](table)
+		items, found, err := users.TransactGet(ctx, []dynago.AttributeRecord{
+			table.NewKeys(pk, dynago.StringValue("user#missing")),
+			table.NewKeys(pk, dynago.StringValue("user#1")),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(items) != 2 || found[0] || !found[1] || items[1].Id != "1" || items[0].Id != "" {
+			t.Errorf("expected missing item followed by user 1; got %v, %v", items, found)
+		}
+	})
+
+	t.Run("same item twice is rejected", func(t *testing.T) {
+		key := table.NewKeys(pk, dynago.StringValue("user#1"))
+		var a, b User
+		_, err := table.TransactGetItems(ctx, []*dynago.TransactGetItemsInput{{Key: key, Out: &a}, {Key: key, Out: &b}})
+		if !errors.Is(err, dynago.ErrValidation) {
+			t.Errorf("expected ErrValidation; got %v", err)
+		}
+	})
+}
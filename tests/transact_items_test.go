@@ -37,7 +37,7 @@ func TestTransactItems(t *testing.T) {
 		},
 		newItems: []Terminal{},
 		operations: []types.TransactWriteItem{
-			table.WithPutItem("merchant1", "terminal1", Terminal{
+			table.WithPutItem(dynago.StringValue("merchant1"), dynago.StringValue("terminal1"), Terminal{
 				Id: "1",
 				Pk: "merchant1",
 				Sk: "terminal1",
@@ -63,8 +63,8 @@ func TestTransactItems(t *testing.T) {
 				Sk: "terminal1",
 			}},
 			operations: []types.TransactWriteItem{
-				table.WithDeleteItem("merchant2", "terminal1"),
-				table.WithPutItem("merchant2", "terminal2", Terminal{
+				table.WithDeleteItem(dynago.StringValue("merchant2"), dynago.StringValue("terminal1")),
+				table.WithPutItem(dynago.StringValue("merchant2"), dynago.StringValue("terminal2"), Terminal{
 					Id: "2",
 					Pk: "merchant2",
 					Sk: "terminal2",
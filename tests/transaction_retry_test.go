@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+// cancelingAPI cancels transactions with the given reason codes, one set of codes per attempt,
+// and records the ClientRequestToken of every attempt
+type cancelingAPI struct {
+	dynago.DynamoDBAPI
+	codes  [][]string
+	tokens []string
+}
+
+func (api *cancelingAPI) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	api.tokens = append(api.tokens, aws.ToString(params.ClientRequestToken))
+	if len(api.codes) == 0 {
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	}
+	codes := api.codes[0]
+	api.codes = api.codes[1:]
+	reasons := make([]types.CancellationReason, len(codes))
+	for i, code := range codes {
+		reasons[i] = types.CancellationReason{Code: aws.String(code)}
+	}
+	return nil, &types.TransactionCanceledException{Message: aws.String("Transaction cancelled"), CancellationReasons: reasons}
+}
+
+func TestTransactionRetry(t *testing.T) {
+	ctx := context.TODO()
+	newTable := func(api *cancelingAPI) *dynago.Client {
+		return dynago.NewClientFromAPI(api, dynago.ClientOptions{
+			TableName:        "transactions",
+			PartitionKeyName: "pk",
+			SortKeyName:      "sk",
+			TransactionRetryPolicy: dynago.RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Millisecond,
+			},
+		})
+	}
+	pk := dynago.StringValue("stock")
+
+	t.Run("conflicts are retried with the same token", func(t *testing.T) {
+		api := &cancelingAPI{codes: [][]string{
+			{"None", "TransactionConflict"},
+			{"ThrottlingError", "None"},
+		}}
+		table := newTable(api)
+		err := table.TransactItems(ctx, table.WithDeleteItem(pk, dynago.StringValue("a")), table.WithDeleteItem(pk, dynago.StringValue("b")))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(api.tokens) != 3 || api.tokens[0] == "" || api.tokens[0] != api.tokens[1] || api.tokens[1] != api.tokens[2] {
+			t.Errorf("expected 3 attempts with the same token; got %v", api.tokens)
+		}
+	})
+
+	t.Run("tx keeps its token across retries", func(t *testing.T) {
+		api := &cancelingAPI{codes: [][]string{{"TransactionConflict"}}}
+		tx := newTable(api).NewTx().Delete(pk, dynago.StringValue("a"))
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if len(api.tokens) != 2 || api.tokens[0] != tx.ClientRequestToken() || api.tokens[1] != tx.ClientRequestToken() {
+			t.Errorf("expected both attempts to send token %s; got %v", tx.ClientRequestToken(), api.tokens)
+		}
+	})
+
+	t.Run("last reasons are returned after max attempts", func(t *testing.T) {
+		conflict := []string{"TransactionConflict"}
+		api := &cancelingAPI{codes: [][]string{conflict, conflict, conflict, conflict}}
+		err := newTable(api).TransactDeleteItems(ctx, []*dynago.TransactDeleteItemsInput{
+			{PartitionKeyValue: pk, SortKeyValue: dynago.StringValue("a")},
+		})
+		var txErr *dynago.TransactionCanceledError
+		if !errors.As(err, &txErr) {
+			t.Fatalf("expected TransactionCanceledError; got %v", err)
+		}
+		if txErr.Attempts != 3 || len(api.tokens) != 3 {
+			t.Errorf("expected 3 attempts; got %d, sent %d", txErr.Attempts, len(api.tokens))
+		}
+		if failed := txErr.Failed(); len(failed) != 1 || failed[0].Code != "TransactionConflict" {
+			t.Errorf("expected TransactionConflict reason; got %v", failed)
+		}
+	})
+
+	t.Run("mixed reasons are not retried", func(t *testing.T) {
+		api := &cancelingAPI{codes: [][]string{{"TransactionConflict", "ConditionalCheckFailed"}}}
+		err := newTable(api).TransactPutItems(ctx, []*dynago.TransactPutItemsInput{
+			{PartitionKeyValue: pk, SortKeyValue: dynago.StringValue("a"), Item: User{Id: "a"}},
+			{PartitionKeyValue: pk, SortKeyValue: dynago.StringValue("b"), Item: User{Id: "b"}},
+		})
+		if !errors.Is(err, dynago.ErrConditionFailed) {
+			t.Errorf("expected ErrConditionFailed; got %v", err)
+		}
+		if len(api.tokens) != 1 {
+			t.Errorf("expected a single attempt; got %d", len(api.tokens))
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+type Stock struct {
+	Units int
+}
+
+// brokenItem fails to marshal
+type brokenItem struct{}
+
+func (brokenItem) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return nil, errors.New("broken item")
+}
+
+func TestTx(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("store#1")
+
+	err := table.PutItem(ctx, pk, dynago.StringValue("stock"), Stock{Units: 1})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	inStock := dynago.WithTxCondition("Units > :zero", map[string]dynago.Attribute{":zero": dynago.NumberValue(0)})
+	order := func(id string) *dynago.Tx {
+		update := dynago.NewUpdate().Add("Units", -1)
+		return table.NewTx().
+			Put(pk, dynago.StringValue("order#"+id), User{Id: id}, dynago.WithTxCondition("attribute_not_exists(pk)", nil)).
+			Update(pk, dynago.StringValue("stock"), update, inStock)
+	}
+
+	t.Run("commit applies every item", func(t *testing.T) {
+		if err := order("1").Commit(ctx); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		var stock Stock
+		err, _ := table.GetItem(ctx, pk, dynago.StringValue("stock"), &stock)
+		if err != nil || stock.Units != 0 {
+			t.Errorf("expected stock to be decremented; got %v, %v", stock, err)
+		}
+	})
+
+	t.Run("failed update condition cancels the transaction", func(t *testing.T) {
+		err := order("2").Commit(ctx)
+		var txErr *dynago.TransactionCanceledError
+		if !errors.As(err, &txErr) {
+			t.Fatalf("expected TransactionCanceledError; got %v", err)
+		}
+		failed := txErr.Failed()
+		if len(failed) != 1 || failed[0].Index != 1 {
+			t.Errorf("expected the update to fail; got %v", failed)
+		}
+		var out User
+		err, found := table.GetItem(ctx, pk, dynago.StringValue("order#2"), &out)
+		if err != nil || found {
+			t.Errorf("expected order not to be created; got %v", err)
+		}
+	})
+
+	t.Run("condition check returns current item", func(t *testing.T) {
+		err := table.NewTx().
+			Delete(pk, dynago.StringValue("order#1")).
+			ConditionCheck(pk, dynago.StringValue("stock"), "Units > :zero",
+				map[string]dynago.Attribute{":zero": dynago.NumberValue(0)},
+				dynago.WithTxReturnItemOnConditionFailure()).
+			Commit(ctx)
+		var txErr *dynago.TransactionCanceledError
+		if !errors.As(err, &txErr) {
+			t.Fatalf("expected TransactionCanceledError; got %v", err)
+		}
+		if failed := txErr.Failed(); len(failed) != 1 || failed[0].Index != 1 || failed[0].Item["Units"] == nil {
+			t.Errorf("expected condition check to fail with the current item; got %v", failed)
+		}
+	})
+
+	t.Run("commit is idempotent", func(t *testing.T) {
+		tx := table.NewTx().Update(pk, dynago.StringValue("counter"), dynago.NewUpdate().Add("Units", 1))
+		for i := 0; i < 2; i++ {
+			if err := tx.Commit(ctx); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+		}
+		var out Stock
+		err, _ := table.GetItem(ctx, pk, dynago.StringValue("counter"), &out)
+		if err != nil || out.Units != 1 {
+			t.Errorf("expected counter to be incremented once; got %v, %v", out, err)
+		}
+	})
+
+	t.Run("build errors are returned without sending the request", func(t *testing.T) {
+		err := table.NewTx().
+			Delete(pk, dynago.StringValue("order#1")).
+			Put(pk, dynago.StringValue("bad"), brokenItem{}).
+			Commit(ctx)
+		if err == nil || !strings.Contains(err.Error(), "transaction item 1") {
+			t.Fatalf("expected marshal error of item 1; got %v", err)
+		}
+		var out User
+		err, found := table.GetItem(ctx, pk, dynago.StringValue("order#1"), &out)
+		if err != nil || !found {
+			t.Errorf("expected order to remain; got %v", err)
+		}
+	})
+}
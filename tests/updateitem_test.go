@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago"
+)
+
+type Profile struct {
+	Pk       string
+	Sk       string
+	Name     string
+	Status   string
+	Visits   int
+	Tags     []string `dynamodbav:",stringset,omitempty"`
+	Comments []string
+	Address  map[string]string
+}
+
+func TestUpdateItem(t *testing.T) {
+	testCases := []struct {
+		title     string
+		source    *Profile
+		update    *dynago.UpdateBuilder
+		opts      []dynago.UpdateOption
+		expected  Profile
+		expectErr bool
+	}{
+		{
+			title:  "set creates missing item",
+			update: dynago.NewUpdate().Set("Name", "Jon").Set("Status", "active"),
+			// key attributes pk and sk are decoded into Pk and Sk
+			expected: Profile{
+				Pk:     "profile",
+				Sk:     "profile",
+				Name:   "Jon",
+				Status: "active",
+			},
+		},
+		{
+			title: "set, remove, add and delete on existing item",
+			source: &Profile{
+				Pk:       "profile",
+				Sk:       "profile",
+				Name:     "Jon",
+				Status:   "active",
+				Visits:   1,
+				Tags:     []string{"a", "b"},
+				Comments: []string{"first"},
+				Address:  map[string]string{"City": "Melbourne", "Unit": "5"},
+			},
+			update: dynago.NewUpdate().
+				Set("Address.City", "Sydney").
+				Remove("Status", "Address.Unit").
+				Add("Visits", 2).
+				Delete("Tags", dynago.StringSetValue("a")).
+				Append("Comments", []string{"second"}).
+				SetIfNotExists("Name", "Arya"),
+			expected: Profile{
+				Pk:       "profile",
+				Sk:       "profile",
+				Name:     "Jon",
+				Visits:   3,
+				Tags:     []string{"b"},
+				Comments: []string{"first", "second"},
+				Address:  map[string]string{"City": "Sydney"},
+			},
+		},
+		{
+			title:     "condition fails on missing item",
+			update:    dynago.NewUpdate().Set("Name", "Jon"),
+			opts:      []dynago.UpdateOption{dynago.WithUpdateCondition("attribute_exists(pk)", nil)},
+			expectErr: true,
+		},
+		{
+			title:     "empty update expression",
+			update:    dynago.NewUpdate(),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			table := prepareTable(t)
+			ctx := context.TODO()
+			pk := dynago.StringValue("profile")
+
+			if tc.source != nil {
+				err := table.PutItem(ctx, pk, pk, tc.source)
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+			}
+
+			attr, err := table.UpdateItem(ctx, pk, pk, tc.update,
+				append(tc.opts, dynago.WithUpdateReturnValues(types.ReturnValueAllNew))...)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected update to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+
+			var got Profile
+			err = attributevalue.UnmarshalMap(attr, &got)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected update to return %v; got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTransactUpdateItem(t *testing.T) {
+	table := prepareTable(t)
+	ctx := context.TODO()
+	pk := dynago.StringValue("profile")
+
+	err := table.PutItem(ctx, pk, pk, Profile{Pk: "profile", Sk: "profile", Visits: 1})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	err = table.TransactItems(ctx,
+		table.WithUpdateItem(pk, pk, dynago.NewUpdate().Add("Visits", 10).Set("Name", "Sansa")),
+		table.WithPutItem(dynago.StringValue("other"), dynago.StringValue("other"), Profile{Pk: "other", Sk: "other"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	var got Profile
+	err, _ = table.GetItem(ctx, pk, pk, &got)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if got.Visits != 11 || got.Name != "Sansa" {
+		t.Errorf("expected transaction to update item; got %v", got)
+	}
+}
+
+type invalidValue struct{}
+
+func (invalidValue) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return nil, errors.New("invalid value")
+}
+
+func TestUpdateBuilder(t *testing.T) {
+	expr, names, values, err := dynago.NewUpdate().
+		Set("Name", "Jon").
+		Set("Address.Name", "Home").
+		Remove("List[1]").
+		Add("Count", 1).
+		Delete("Tags", dynago.StringSetValue("a")).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	expected := "SET #u0 = :u0, #u1.#u0 = :u1 REMOVE #u2[1] ADD #u3 :u2 DELETE #u4 :u3"
+	if expr != expected {
+		t.Errorf("expected expression %s; got %s", expected, expr)
+	}
+	if len(names) != 5 || names["#u0"] != "Name" || names["#u2"] != "List" {
+		t.Errorf("unexpected names %v", names)
+	}
+	if len(values) != 4 {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	_, _, _, err = dynago.NewUpdate().Set("Invalid", invalidValue{}).Build()
+	if err == nil || !strings.Contains(err.Error(), "marshal") {
+		t.Errorf("expected marshal error; got %v", err)
+	}
+}
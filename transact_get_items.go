@@ -0,0 +1,69 @@
+package dynago
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago/expression"
+)
+
+// TransactGetItemsInput describes an item read by TransactGetItems
+type TransactGetItemsInput struct {
+	// Key of the item, built using NewKeys
+	Key map[string]Attribute
+	// Attributes to read, every attribute is read when empty
+	Fields []string
+	// Destination the item is unmarshalled into, left unchanged when the item does not exist
+	Out interface{}
+}
+
+// TransactGetItems reads up to 100 items as a single serializable snapshot, no write is applied between the reads.
+// found has an entry per input in request order, reporting whether the item exists
+//
+//	var account Account
+//	var head LedgerEntry
+//	found, err := table.TransactGetItems(ctx, []*dynago.TransactGetItemsInput{
+//	  {Key: table.NewKeys(pk, dynago.StringValue("account")), Out: &account},
+//	  {Key: table.NewKeys(pk, dynago.StringValue("ledger#head")), Fields: []string{"Seq", "Balance"}, Out: &head},
+//	})
+func (t *Client) TransactGetItems(ctx context.Context, inputs []*TransactGetItemsInput) (found []bool, err error) {
+	ctx, op := t.startOperation(ctx, "TransactGetItems")
+	defer func() { op.end(err) }()
+
+	if len(inputs) > MaxTransactionItems {
+		return nil, &TransactionSizeError{Items: len(inputs)}
+	}
+	requests := make([]types.TransactGetItem, len(inputs))
+	for idx, in := range inputs {
+		get := &types.Get{TableName: &t.TableName, Key: in.Key}
+		if len(in.Fields) > 0 {
+			projection, names := expression.Projection(in.Fields...).Build(nil)
+			get.ProjectionExpression, get.ExpressionAttributeNames = aws.String(projection), names
+		}
+		requests[idx] = types.TransactGetItem{Get: get}
+	}
+
+	resp, err := t.client.TransactGetItems(ctx, &dynamodb.TransactGetItemsInput{
+		TransactItems: requests,
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	found = make([]bool, len(inputs))
+	for idx, res := range resp.Responses {
+		if idx >= len(inputs) || res.Item == nil {
+			continue
+		}
+		found[idx] = true
+		op.addItems(1)
+		if err := attributevalue.UnmarshalMap(res.Item, inputs[idx].Out); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal item %d; %w", idx, err)
+		}
+	}
+	return found, nil
+}
@@ -2,37 +2,36 @@ package dynago
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
-func (t *Client) WithDeleteItem(pk string, sk string) types.TransactWriteItem {
+// WithDeleteItem creates a transaction write item that deletes the item with given keys
+func (t *Client) WithDeleteItem(pk, sk Attribute) types.TransactWriteItem {
 	return types.TransactWriteItem{
 		Delete: &types.Delete{
 			TableName: &t.TableName,
-			Key: map[string]types.AttributeValue{
-				"pk": &types.AttributeValueMemberS{Value: pk},
-				"sk": &types.AttributeValueMemberS{Value: sk},
-			},
+			Key:       t.NewKeys(pk, sk),
 		},
 	}
 
 }
 
-func (t *Client) WithPutItem(pk string, sk string, item interface{}) types.TransactWriteItem {
+// WithPutItem creates a transaction write item that puts the item with given keys.
+// An item that cannot be marshalled is logged and results in an empty write item that TransactItems rejects,
+// use NewTx to get the error instead
+func (t *Client) WithPutItem(pk, sk Attribute, item interface{}) types.TransactWriteItem {
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
-		log.Println("Failed to Marshal item" + err.Error())
+		t.log().Error("failed to marshal item", slog.Any("error", err))
 		return types.TransactWriteItem{}
 	}
-	keys := map[string]types.AttributeValue{
-		"pk": &types.AttributeValueMemberS{Value: pk},
-		"sk": &types.AttributeValueMemberS{Value: sk},
-	}
-	for k, v := range keys {
+	for k, v := range t.NewKeys(pk, sk) {
 		av[k] = v
 	}
 	return types.TransactWriteItem{
@@ -44,11 +43,97 @@ func (t *Client) WithPutItem(pk string, sk string, item interface{}) types.Trans
 
 }
 
-// TransactItems is a synchronous for writing or deletion operation performed in dynamodb grouped together
+// WithPut creates a transaction write item that puts the item using the keys provided by the item, see ItemKeys.
+// An item without keys is logged and results in an empty write item, see WithPutItem
+func (t *Client) WithPut(item interface{}) types.TransactWriteItem {
+	pk, sk, err := t.itemKeys(item)
+	if err != nil {
+		t.log().Error("failed to read item keys", slog.Any("error", err))
+		return types.TransactWriteItem{}
+	}
+	return t.WithPutItem(pk, sk, item)
+}
+
+// WithDelete creates a transaction write item that deletes the item using the keys provided by the item, see ItemKeys.
+// An item without keys is logged and results in an empty write item, see WithPutItem
+func (t *Client) WithDelete(item interface{}) types.TransactWriteItem {
+	pk, sk, err := t.itemKeys(item)
+	if err != nil {
+		t.log().Error("failed to read item keys", slog.Any("error", err))
+		return types.TransactWriteItem{}
+	}
+	return t.WithDeleteItem(pk, sk)
+}
 
-func (t *Client) TransactItems(ctx context.Context, input ...types.TransactWriteItem) error {
-	_, err := t.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: input,
-	})
-	return err
+// WithUpdateItem creates a transaction write item that applies the update expression to the item with given keys.
+// An update that cannot be built, eg: an empty update, is logged and results in an empty write item, see WithPutItem
+func (t *Client) WithUpdateItem(pk, sk Attribute, update *UpdateBuilder) types.TransactWriteItem {
+	expr, names, values, err := update.Build()
+	if err != nil {
+		t.log().Error("failed to build update expression", slog.Any("error", err))
+		return types.TransactWriteItem{}
+	}
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                 &t.TableName,
+			Key:                       t.NewKeys(pk, sk),
+			UpdateExpression:          &expr,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		},
+	}
+}
+
+// TransactItems is a synchronous for writing or deletion operation performed in dynamodb grouped together.
+// Transactions with more than 100 items, items larger than 400KB or 4MB of items in total are rejected before sending.
+// Transactions canceled by conflicts or throttling are retried using ClientOptions.TransactionRetryPolicy
+func (t *Client) TransactItems(ctx context.Context, input ...types.TransactWriteItem) (err error) {
+	ctx, op := t.startOperation(ctx, "TransactItems")
+	defer func() { op.end(err) }()
+
+	if err := checkTransaction(input); err != nil {
+		return err
+	}
+	return t.transactWriteItems(ctx, input, newRequestToken())
+}
+
+// transactWriteItems sends the transaction, retrying it while it fails for transient reasons only.
+// Every attempt sends the same ClientRequestToken so a transaction applied by an attempt whose response was lost is
+// not applied twice. The error of the last attempt is returned with the cancellation reasons and number of attempts
+func (t *Client) transactWriteItems(ctx context.Context, items []types.TransactWriteItem, token string) error {
+	policy := t.txRetry.orDefault()
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems:      items,
+		ClientRequestToken: &token,
+	}
+	op := operationFrom(ctx)
+	op.addItems(len(items))
+	for attempt := 1; ; attempt++ {
+		op.setAttempts(attempt)
+		_, err := t.client.TransactWriteItems(ctx, input)
+		if err == nil {
+			return nil
+		}
+		err = wrapError(err)
+		var canceled *TransactionCanceledError
+		if errors.As(err, &canceled) {
+			canceled.Attempts = attempt
+		}
+		if attempt >= policy.MaxAttempts || !retryableTransaction(err) {
+			return err
+		}
+		if werr := policy.wait(ctx, attempt); werr != nil {
+			return fmt.Errorf("%w; %w", werr, err)
+		}
+	}
+}
+
+// retryableTransaction reports whether a failed transaction may succeed when sent again
+func retryableTransaction(err error) bool {
+	var canceled *TransactionCanceledError
+	if errors.As(err, &canceled) {
+		return canceled.Retryable()
+	}
+	var inProgress *types.TransactionInProgressException
+	return errors.Is(err, ErrThrottled) || errors.As(err, &inProgress)
 }
@@ -0,0 +1,289 @@
+package dynago
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago/expression"
+)
+
+// TxOption sets the condition of a transaction item
+type TxOption func(*txCondition) error
+
+// txCondition holds the condition fields shared by every kind of transaction item
+type txCondition struct {
+	expression *string
+	names      map[string]string
+	values     map[string]Attribute
+	returnItem bool
+}
+
+// WithTxCondition only applies the transaction item if the condition expression evaluates to true,
+// otherwise the whole transaction is canceled with a reason matching ErrConditionFailed.
+// values are merged with the values of an update expression
+func WithTxCondition(condition string, values map[string]Attribute) TxOption {
+	return func(c *txCondition) error {
+		c.expression = &condition
+		if len(values) == 0 {
+			return nil
+		}
+		if c.values == nil {
+			c.values = map[string]Attribute{}
+		}
+		for k, v := range values {
+			if _, ok := c.values[k]; ok {
+				return fmt.Errorf("condition value %s conflicts with update expression value", k)
+			}
+			c.values[k] = v
+		}
+		return nil
+	}
+}
+
+// WithTxConditionExpr only applies the transaction item if a condition built by the expression package evaluates to true.
+// Use it with ConditionCheck and an empty condition to check a built condition
+//
+//	tx.ConditionCheck(pk, sk, "", nil, dynago.WithTxConditionExpr(expression.Attr("Active").Equal(dynago.BoolValue(true))))
+func WithTxConditionExpr(condition expression.Condition) TxOption {
+	return func(c *txCondition) error {
+		if condition.IsZero() {
+			return nil
+		}
+		expr, err := buildExpression(&c.names, &c.values, condition.Build)
+		if err != nil {
+			return err
+		}
+		c.expression = aws.String(expr)
+		return nil
+	}
+}
+
+// WithTxReturnItemOnConditionFailure includes the current item in the CancellationReason of a failed condition
+func WithTxReturnItemOnConditionFailure() TxOption {
+	return func(c *txCondition) error {
+		c.returnItem = true
+		return nil
+	}
+}
+
+func (c *txCondition) returnValues() types.ReturnValuesOnConditionCheckFailure {
+	if c.returnItem {
+		return types.ReturnValuesOnConditionCheckFailureAllOld
+	}
+	return ""
+}
+
+// Tx builds a transaction of up to 100 put, update, delete and condition check items that are applied atomically.
+// Errors building an item, eg: marshalling, are collected and returned by Commit without sending the request.
+//
+// Every transaction gets a ClientRequestToken, committing the same Tx again within 10 minutes is idempotent
+//
+//	err := table.NewTx().
+//	  Put(pk, dynago.StringValue("order#1"), order, dynago.WithTxCondition("attribute_not_exists(pk)", nil)).
+//	  Update(pk, dynago.StringValue("stock"), dynago.NewUpdate().Add("Units", -1)).
+//	  ConditionCheck(pk, dynago.StringValue("account"), "Active = :active", map[string]dynago.Attribute{
+//	    ":active": dynago.BoolValue(true),
+//	  }).
+//	  Commit(ctx)
+//
+// Use On to add items of other tables to the same transaction
+type Tx struct {
+	// client of the table the items are added to
+	client *Client
+	*txState
+}
+
+// txState is shared by a transaction and its views on other tables
+type txState struct {
+	// client that created the transaction, it sends the request
+	owner *Client
+	items []types.TransactWriteItem
+	token string
+	errs  []error
+}
+
+// NewTx creates an empty transaction on the table of the client
+func (t *Client) NewTx() *Tx {
+	return &Tx{client: t, txState: &txState{owner: t, token: newRequestToken()}}
+}
+
+// On returns a view of the transaction that adds items to the table of the given client, using its key names.
+// Items added through any view belong to the same transaction and are committed atomically by Commit.
+// The client must share the DynamoDB connection of the client that created the transaction
+//
+//	tx := orders.NewTx().Put(orderPk, orderSk, order)
+//	tx.On(inventory).Update(itemPk, itemSk, dynago.NewUpdate().Add("Units", -1),
+//	  dynago.WithTxCondition("Units > :zero", map[string]dynago.Attribute{":zero": dynago.NumberValue(0)}))
+//	err := tx.Commit(ctx)
+func (tx *Tx) On(client *Client) *Tx {
+	return &Tx{client: client, txState: tx.txState}
+}
+
+func newRequestToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithClientRequestToken replaces the generated idempotency token of the transaction
+func (tx *Tx) WithClientRequestToken(token string) *Tx {
+	tx.token = token
+	return tx
+}
+
+// ClientRequestToken returns the idempotency token sent with the transaction
+func (tx *Tx) ClientRequestToken() string {
+	return tx.token
+}
+
+// Put creates or replaces the item at the given keys
+func (tx *Tx) Put(pk, sk Attribute, item interface{}, opts ...TxOption) *Tx {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return tx.fail(fmt.Errorf("failed to marshal item; %w", err))
+	}
+	for k, v := range tx.client.NewKeys(pk, sk) {
+		av[k] = v
+	}
+	cond, err := newTxCondition(nil, opts)
+	if err != nil {
+		return tx.fail(err)
+	}
+	return tx.add(types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:                           &tx.client.TableName,
+			Item:                                av,
+			ConditionExpression:                 cond.expression,
+			ExpressionAttributeNames:            cond.names,
+			ExpressionAttributeValues:           cond.values,
+			ReturnValuesOnConditionCheckFailure: cond.returnValues(),
+		},
+	})
+}
+
+// Update applies the update expression to the item at the given keys
+func (tx *Tx) Update(pk, sk Attribute, update *UpdateBuilder, opts ...TxOption) *Tx {
+	expr, names, values, err := update.Build()
+	if err != nil {
+		return tx.fail(err)
+	}
+	cond, err := newTxCondition(&txCondition{names: names, values: values}, opts)
+	if err != nil {
+		return tx.fail(err)
+	}
+	return tx.add(types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                           &tx.client.TableName,
+			Key:                                 tx.client.NewKeys(pk, sk),
+			UpdateExpression:                    &expr,
+			ConditionExpression:                 cond.expression,
+			ExpressionAttributeNames:            cond.names,
+			ExpressionAttributeValues:           cond.values,
+			ReturnValuesOnConditionCheckFailure: cond.returnValues(),
+		},
+	})
+}
+
+// Delete deletes the item at the given keys
+func (tx *Tx) Delete(pk, sk Attribute, opts ...TxOption) *Tx {
+	cond, err := newTxCondition(nil, opts)
+	if err != nil {
+		return tx.fail(err)
+	}
+	return tx.add(types.TransactWriteItem{
+		Delete: &types.Delete{
+			TableName:                           &tx.client.TableName,
+			Key:                                 tx.client.NewKeys(pk, sk),
+			ConditionExpression:                 cond.expression,
+			ExpressionAttributeNames:            cond.names,
+			ExpressionAttributeValues:           cond.values,
+			ReturnValuesOnConditionCheckFailure: cond.returnValues(),
+		},
+	})
+}
+
+// ConditionCheck cancels the transaction unless the condition holds for the item at the given keys.
+// The item is not modified
+func (tx *Tx) ConditionCheck(pk, sk Attribute, condition string, values map[string]Attribute, opts ...TxOption) *Tx {
+	cond, err := newTxCondition(nil, append([]TxOption{WithTxCondition(condition, values)}, opts...))
+	if err != nil {
+		return tx.fail(err)
+	}
+	return tx.add(types.TransactWriteItem{
+		ConditionCheck: &types.ConditionCheck{
+			TableName:                           &tx.client.TableName,
+			Key:                                 tx.client.NewKeys(pk, sk),
+			ConditionExpression:                 cond.expression,
+			ExpressionAttributeNames:            cond.names,
+			ExpressionAttributeValues:           cond.values,
+			ReturnValuesOnConditionCheckFailure: cond.returnValues(),
+		},
+	})
+}
+
+func newTxCondition(cond *txCondition, opts []TxOption) (*txCondition, error) {
+	if cond == nil {
+		cond = &txCondition{}
+	}
+	for _, opt := range opts {
+		if err := opt(cond); err != nil {
+			return nil, err
+		}
+	}
+	return cond, nil
+}
+
+func (tx *Tx) add(item types.TransactWriteItem) *Tx {
+	if tx.client != tx.owner && !sameAPI(tx.client.client, tx.owner.client) {
+		return tx.fail(fmt.Errorf("%w; table %s does not share the connection of table %s", ErrValidation, tx.client.TableName, tx.owner.TableName))
+	}
+	tx.items = append(tx.items, item)
+	return tx
+}
+
+// sameAPI reports whether two clients send requests using the same DynamoDB API implementation
+func sameAPI(a, b DynamoDBAPI) bool {
+	a, b = rawAPI(a), rawAPI(b)
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta != nil && ta.Comparable() && a == b
+}
+
+// fail records an error of the item being added, keeping its position so errors name the item index
+func (tx *Tx) fail(err error) *Tx {
+	tx.errs = append(tx.errs, fmt.Errorf("transaction item %d; %w", len(tx.items), err))
+	tx.items = append(tx.items, types.TransactWriteItem{})
+	return tx
+}
+
+// Items returns the write items of the transaction
+func (tx *Tx) Items() []types.TransactWriteItem {
+	return tx.items
+}
+
+// Commit applies every item of the transaction atomically.
+// Returns the errors collected while building the transaction without sending it, a *TransactionCanceledError
+// when DynamoDB canceled the transaction or an error matching one of the sentinel errors.
+// Transactions canceled by conflicts or throttling are retried with the same ClientRequestToken using
+// ClientOptions.TransactionRetryPolicy
+func (tx *Tx) Commit(ctx context.Context) (err error) {
+	ctx, op := tx.owner.startOperation(ctx, "Tx.Commit")
+	defer func() { op.end(err) }()
+
+	if len(tx.errs) > 0 {
+		return errors.Join(tx.errs...)
+	}
+	if len(tx.items) == 0 {
+		return fmt.Errorf("%w; transaction has no items", ErrValidation)
+	}
+	if err := checkTransaction(tx.items); err != nil {
+		return err
+	}
+	return tx.owner.transactWriteItems(ctx, tx.items, tx.token)
+}
@@ -0,0 +1,256 @@
+package dynago
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/oolio-group/dynago/expression"
+)
+
+type UpdateItemInput = dynamodb.UpdateItemInput
+
+type UpdateOption func(*dynamodb.UpdateItemInput) error
+
+// UpdateBuilder builds a DynamoDB update expression out of SET, REMOVE, ADD and DELETE actions.
+// Attribute name and value placeholders are generated automatically so reserved words can be used as names.
+//
+// Names are document paths; nested attributes are separated by dots and list elements are selected with [n]
+//
+//	update := dynago.NewUpdate().
+//	  Set("Status", "active").
+//	  Add("LoginCount", 1).
+//	  Remove("Address.Unit")
+type UpdateBuilder struct {
+	set    []string
+	remove []string
+	add    []string
+	delete []string
+
+	names  map[string]string
+	values map[string]Attribute
+	err    error
+}
+
+// NewUpdate creates an empty update expression builder
+func NewUpdate() *UpdateBuilder {
+	return &UpdateBuilder{
+		names:  map[string]string{},
+		values: map[string]Attribute{},
+	}
+}
+
+// Set replaces the attribute at name with value. value can be any type supported by attributevalue.Marshal or an Attribute
+func (u *UpdateBuilder) Set(name string, value interface{}) *UpdateBuilder {
+	u.set = append(u.set, fmt.Sprintf("%s = %s", u.name(name), u.value(value)))
+	return u
+}
+
+// SetIfNotExists sets the attribute at name to value only if the attribute does not exist yet
+func (u *UpdateBuilder) SetIfNotExists(name string, value interface{}) *UpdateBuilder {
+	path := u.name(name)
+	u.set = append(u.set, fmt.Sprintf("%s = if_not_exists(%s, %s)", path, path, u.value(value)))
+	return u
+}
+
+// Append adds the elements of list value to the end of the list attribute at name
+func (u *UpdateBuilder) Append(name string, value interface{}) *UpdateBuilder {
+	path := u.name(name)
+	u.set = append(u.set, fmt.Sprintf("%s = list_append(%s, %s)", path, path, u.value(value)))
+	return u
+}
+
+// Remove deletes the attributes at the given names from the item
+func (u *UpdateBuilder) Remove(names ...string) *UpdateBuilder {
+	for _, name := range names {
+		u.remove = append(u.remove, u.name(name))
+	}
+	return u
+}
+
+// Add increments a number attribute by value or adds the elements of a set value to a set attribute.
+// Missing attributes are treated as 0 or an empty set
+func (u *UpdateBuilder) Add(name string, value interface{}) *UpdateBuilder {
+	u.add = append(u.add, fmt.Sprintf("%s %s", u.name(name), u.value(value)))
+	return u
+}
+
+// Delete removes the elements of set value from the set attribute at name
+func (u *UpdateBuilder) Delete(name string, value interface{}) *UpdateBuilder {
+	u.delete = append(u.delete, fmt.Sprintf("%s %s", u.name(name), u.value(value)))
+	return u
+}
+
+// Build returns the update expression along with the attribute names and values referenced by it.
+// Returns an error if no action was added or a value could not be marshalled
+func (u *UpdateBuilder) Build() (expression string, names map[string]string, values map[string]Attribute, err error) {
+	if u.err != nil {
+		return "", nil, nil, u.err
+	}
+
+	clauses := make([]string, 0, 4)
+	for _, action := range []struct {
+		keyword string
+		list    []string
+	}{
+		{"SET", u.set},
+		{"REMOVE", u.remove},
+		{"ADD", u.add},
+		{"DELETE", u.delete},
+	} {
+		if len(action.list) > 0 {
+			clauses = append(clauses, action.keyword+" "+strings.Join(action.list, ", "))
+		}
+	}
+	if len(clauses) == 0 {
+		return "", nil, nil, fmt.Errorf("update expression is empty")
+	}
+
+	// DynamoDB rejects empty expression attribute maps. Copies are returned so options can add to them
+	if len(u.names) > 0 {
+		names = make(map[string]string, len(u.names))
+		for k, v := range u.names {
+			names[k] = v
+		}
+	}
+	if len(u.values) > 0 {
+		values = make(map[string]Attribute, len(u.values))
+		for k, v := range u.values {
+			values[k] = v
+		}
+	}
+	return strings.Join(clauses, " "), names, values, nil
+}
+
+// name returns the placeholder path for the given document path, reusing placeholders of known attribute names
+func (u *UpdateBuilder) name(path string) string {
+	segments := strings.Split(path, ".")
+	for idx, segment := range segments {
+		index := ""
+		if i := strings.IndexByte(segment, '['); i > 0 {
+			segment, index = segment[:i], segment[i:]
+		}
+
+		placeholder := ""
+		for k, v := range u.names {
+			if v == segment {
+				placeholder = k
+				break
+			}
+		}
+		if placeholder == "" {
+			placeholder = "#u" + strconv.Itoa(len(u.names))
+			u.names[placeholder] = segment
+		}
+		segments[idx] = placeholder + index
+	}
+	return strings.Join(segments, ".")
+}
+
+// value returns a new placeholder for value
+func (u *UpdateBuilder) value(value interface{}) string {
+	placeholder := ":u" + strconv.Itoa(len(u.values))
+	av, ok := value.(Attribute)
+	if !ok {
+		var err error
+		av, err = attributevalue.Marshal(value)
+		if err != nil {
+			if u.err == nil {
+				u.err = fmt.Errorf("failed to marshal update value for %s; %w", placeholder, err)
+			}
+			return placeholder
+		}
+	}
+	u.values[placeholder] = av
+	return placeholder
+}
+
+// WithUpdateCondition only applies the update if the condition expression evaluates to true.
+// values are merged with the values of the update expression. ExpressionAttributeNames can not be passed, so use
+// WithUpdateConditionExpr for conditions on reserved words such as Status
+func WithUpdateCondition(condition string, values map[string]Attribute) UpdateOption {
+	return func(input *dynamodb.UpdateItemInput) error {
+		input.ConditionExpression = &condition
+		if len(values) == 0 {
+			return nil
+		}
+		if input.ExpressionAttributeValues == nil {
+			input.ExpressionAttributeValues = map[string]Attribute{}
+		}
+		for k, v := range values {
+			if _, ok := input.ExpressionAttributeValues[k]; ok {
+				return fmt.Errorf("condition value %s conflicts with update expression value", k)
+			}
+			input.ExpressionAttributeValues[k] = v
+		}
+		return nil
+	}
+}
+
+// WithUpdateConditionExpr only applies the update if a condition built by the expression package evaluates to true.
+// Placeholders are added next to the placeholders of the update expression
+func WithUpdateConditionExpr(condition expression.Condition) UpdateOption {
+	return func(input *dynamodb.UpdateItemInput) error {
+		if condition.IsZero() {
+			return nil
+		}
+		expr, err := buildExpression(&input.ExpressionAttributeNames, &input.ExpressionAttributeValues, condition.Build)
+		if err != nil {
+			return err
+		}
+		input.ConditionExpression = aws.String(expr)
+		return nil
+	}
+}
+
+// WithUpdateReturnValues sets which item attributes UpdateItem returns, eg: types.ReturnValueAllNew
+func WithUpdateReturnValues(v types.ReturnValue) UpdateOption {
+	return func(input *dynamodb.UpdateItemInput) error {
+		input.ReturnValues = v
+		return nil
+	}
+}
+
+// UpdateItem edits the attributes of the item with given partition key and sort key using an update expression.
+// The item is created if it does not exist; use WithUpdateCondition with attribute_exists to only update existing items.
+//
+// Returned attributes are empty unless WithUpdateReturnValues is used
+//
+//	attr, err := table.UpdateItem(ctx, pk, sk, dynago.NewUpdate().Add("Count", 1), dynago.WithUpdateReturnValues(types.ReturnValueUpdatedNew))
+func (t *Client) UpdateItem(ctx context.Context, pk, sk Attribute, update *UpdateBuilder, opts ...UpdateOption) (_ map[string]Attribute, err error) {
+	ctx, op := t.startOperation(ctx, "UpdateItem")
+	defer func() { op.end(err) }()
+
+	expr, names, values, err := update.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 &t.TableName,
+		Key:                       t.NewKeys(pk, sk),
+		UpdateExpression:          &expr,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+	for _, opt := range opts {
+		if err := opt(input); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.client.UpdateItem(ctx, input)
+	if err != nil {
+		t.log().ErrorContext(ctx, "failed to update item", t.keyAttr(input.Key), slog.Any("error", err))
+		return nil, wrapError(err)
+	}
+
+	op.addItems(1)
+	return resp.Attributes, nil
+}